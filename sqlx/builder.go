@@ -0,0 +1,493 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	errNoTable   = errors.New("no table specified")
+	errNoColumns = errors.New("no columns specified")
+
+	errNoConflictColumns = errors.New("no conflict columns specified to update on conflicts")
+)
+
+// Columns returns the column names of a struct, it follows the same tag rules
+// with UnmarshalRow and UnmarshalRows, which means the fields of anonymous
+// structs and the struct fields tagged with prefix will be expanded, v can be
+// a struct value, a pointer of struct or a nil pointer of struct type, an error
+// will be returned if v is not a struct or it can not be scanned into, such as
+// an unexported field with db tag. The names of the fields without db tag are
+// mapped by the NameMapper of opts.
+func Columns(v interface{}, opts ...Option) ([]string, error) {
+	info, err := getStructInfoOf(v)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := newOptions(opts).nameMapper
+	columns := make([]string, len(info.fields))
+	for i, field := range info.fields {
+		columns[i] = field.columnName(mapper)
+	}
+
+	return columns, nil
+}
+
+// Field describes a struct field mapped to a column
+type Field struct {
+	// Column is the column name of the field
+	Column string
+	// Path is the path of the field, such as Bar.IdNumber
+	Path string
+	// Tag is the parsed db tag of the field
+	Tag Tag
+}
+
+// Fields returns the fields of a struct which are mapped to columns in the
+// same way with UnmarshalRow, the callers can build their own SQL with the
+// tag options such as Tag.PrimaryKey and Tag.ReadOnly.
+func Fields(v interface{}, opts ...Option) ([]Field, error) {
+	info, err := getStructInfoOf(v)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := newOptions(opts).nameMapper
+	fields := make([]Field, len(info.fields))
+	for i, field := range info.fields {
+		fields[i] = Field{
+			Column: field.columnName(mapper),
+			Path:   field.path,
+			Tag:    field.tag,
+		}
+	}
+
+	return fields, nil
+}
+
+// getStructInfoOf returns the structInfo of v, which is a struct value, a
+// pointer of struct or a nil pointer of struct type.
+func getStructInfoOf(v interface{}) (*structInfo, error) {
+	if v == nil {
+		return nil, errNotStruct
+	}
+
+	t := indirect(reflect.TypeOf(v))
+	if t.Kind() != reflect.Struct {
+		return nil, errNotStruct
+	}
+
+	return getStructInfo(t)
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type conditions struct {
+	exprs []string
+	args  []interface{}
+}
+
+func (c *conditions) add(expr string, args ...interface{}) {
+	c.exprs = append(c.exprs, expr)
+	c.args = append(c.args, args...)
+}
+
+func (c *conditions) build(sb *strings.Builder, args []interface{}) []interface{} {
+	if len(c.exprs) == 0 {
+		return args
+	}
+
+	sb.WriteString(" WHERE ")
+	if len(c.exprs) == 1 {
+		sb.WriteString(c.exprs[0])
+	} else {
+		for i, expr := range c.exprs {
+			if i > 0 {
+				sb.WriteString(" AND ")
+			}
+			sb.WriteString("(" + expr + ")")
+		}
+	}
+
+	return append(args, c.args...)
+}
+
+// SelectBuilder builds a SELECT statement
+type SelectBuilder struct {
+	columns  []string
+	table    string
+	joins    []string
+	joinArgs []interface{}
+	where    conditions
+	orderBy  []string
+	limit    int
+	offset   int
+	dialect  Dialect
+}
+
+// Select returns a SelectBuilder which selects the given columns, the columns
+// of a struct can be obtained by Columns.
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{
+		columns: columns,
+	}
+}
+
+// Dialect sets the Dialect of the statement, MySQL is used by default
+func (b *SelectBuilder) Dialect(d Dialect) *SelectBuilder {
+	b.dialect = d
+	return b
+}
+
+// From sets the table to select from
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Join appends an INNER JOIN clause
+func (b *SelectBuilder) Join(table, on string, args ...interface{}) *SelectBuilder {
+	return b.join("JOIN", table, on, args...)
+}
+
+// LeftJoin appends a LEFT JOIN clause
+func (b *SelectBuilder) LeftJoin(table, on string, args ...interface{}) *SelectBuilder {
+	return b.join("LEFT JOIN", table, on, args...)
+}
+
+// RightJoin appends a RIGHT JOIN clause
+func (b *SelectBuilder) RightJoin(table, on string, args ...interface{}) *SelectBuilder {
+	return b.join("RIGHT JOIN", table, on, args...)
+}
+
+func (b *SelectBuilder) join(kind, table, on string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, fmt.Sprintf("%s %s ON %s", kind, table, on))
+	b.joinArgs = append(b.joinArgs, args...)
+	return b
+}
+
+// Where appends a condition, multiple conditions are joined with AND
+func (b *SelectBuilder) Where(cond string, args ...interface{}) *SelectBuilder {
+	b.where.add(cond, args...)
+	return b
+}
+
+// OrderBy appends ORDER BY expressions, such as "id DESC"
+func (b *SelectBuilder) OrderBy(orderBy ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, orderBy...)
+	return b
+}
+
+// Limit sets the LIMIT clause, it will be ignored if limit is not positive
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+// Offset sets the OFFSET clause, it will be ignored if offset is not positive
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+// Build returns the sql statement and its arguments
+func (b *SelectBuilder) Build() (string, []interface{}, error) {
+	if len(b.columns) == 0 {
+		return "", nil, errNoColumns
+	}
+
+	if b.table == "" {
+		return "", nil, errNoTable
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(b.columns, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.table)
+	for _, join := range b.joins {
+		sb.WriteString(" " + join)
+	}
+	args = append(args, b.joinArgs...)
+	args = b.where.build(&sb, args)
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+	d := dialectOrDefault(b.dialect)
+	if clause := d.LimitOffset(b.limit, b.offset); clause != "" {
+		sb.WriteString(" " + clause)
+	}
+
+	return Rebind(d, sb.String()), args, nil
+}
+
+// InsertBuilder builds an INSERT statement
+type InsertBuilder struct {
+	table   string
+	columns []string
+	values  [][]interface{}
+	upsert  *upsert
+	dialect Dialect
+	err     error
+}
+
+type upsert struct {
+	conflictColumns []string
+	updateColumns   []string
+}
+
+// Insert returns an InsertBuilder which inserts into the given table
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{
+		table: table,
+	}
+}
+
+// Dialect sets the Dialect of the statement, MySQL is used by default
+func (b *InsertBuilder) Dialect(d Dialect) *InsertBuilder {
+	b.dialect = d
+	return b
+}
+
+// Upsert updates the given columns with the inserting values if there is a
+// conflict on the conflict columns, or does nothing if there are no columns
+// to update, see Dialect.Upsert. The conflict columns are required to update
+// on PostgreSQL and SQLite.
+func (b *InsertBuilder) Upsert(conflictColumns []string, updateColumns ...string) *InsertBuilder {
+	b.upsert = &upsert{
+		conflictColumns: conflictColumns,
+		updateColumns:   updateColumns,
+	}
+	return b
+}
+
+// Columns sets the columns to insert
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values appends a row of values, it can be called multiple times to insert
+// multiple rows.
+func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	b.values = append(b.values, values)
+	return b
+}
+
+// Struct appends a row of values marshaled from a struct by MarshalRow, the
+// columns will be set by the first struct if they are not specified, and
+// the following structs must have the same columns, opts are passed to MarshalRow.
+// The BeforeInsert of v is called before marshaling if v implements BeforeInserter.
+func (b *InsertBuilder) Struct(v interface{}, opts ...Option) *InsertBuilder {
+	v = addressable(v)
+	if err := beforeInsert(v); err != nil {
+		b.err = err
+		return b
+	}
+
+	columns, values, err := MarshalRow(v, opts...)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	if len(b.columns) == 0 {
+		b.columns = columns
+	} else if !equalColumns(b.columns, columns) {
+		b.err = fmt.Errorf("expected columns %v, but found %v", b.columns, columns)
+		return b
+	}
+
+	return b.Values(values...)
+}
+
+// Build returns the sql statement and its arguments
+func (b *InsertBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if b.table == "" {
+		return "", nil, errNoTable
+	}
+
+	if len(b.columns) == 0 {
+		return "", nil, errNoColumns
+	}
+
+	if len(b.values) == 0 {
+		return "", nil, errors.New("no values specified")
+	}
+
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(b.columns)), ", ") + ")"
+	rows := make([]string, 0, len(b.values))
+	var args []interface{}
+	for _, values := range b.values {
+		if len(values) != len(b.columns) {
+			return "", nil, fmt.Errorf("expected value num %d, but found %d", len(b.columns), len(values))
+		}
+
+		rows = append(rows, placeholders)
+		args = append(args, values...)
+	}
+
+	d := dialectOrDefault(b.dialect)
+	if b.upsert != nil && len(b.upsert.updateColumns) > 0 && len(b.upsert.conflictColumns) == 0 && usesOnConflict(d) {
+		return "", nil, errNoConflictColumns
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", b.table, strings.Join(b.columns, ", "), strings.Join(rows, ", "))
+	if b.upsert != nil {
+		if clause := d.Upsert(b.upsert.conflictColumns, b.upsert.updateColumns); clause != "" {
+			query += " " + clause
+		}
+	}
+
+	return Rebind(d, query), args, nil
+}
+
+// UpdateBuilder builds an UPDATE statement
+type UpdateBuilder struct {
+	table   string
+	columns []string
+	values  []interface{}
+	where   conditions
+	dialect Dialect
+	err     error
+}
+
+// Update returns an UpdateBuilder which updates the given table
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{
+		table: table,
+	}
+}
+
+// Dialect sets the Dialect of the statement, MySQL is used by default
+func (b *UpdateBuilder) Dialect(d Dialect) *UpdateBuilder {
+	b.dialect = d
+	return b
+}
+
+// Set appends a column assignment
+func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	b.columns = append(b.columns, column)
+	b.values = append(b.values, value)
+	return b
+}
+
+// Struct appends the column assignments marshaled from a struct by MarshalRow,
+// the fields tagged with pk are skipped so that the primary key is not rewritten,
+// use Where to locate the row by it, opts are passed to MarshalRow. The
+// BeforeUpdate of v is called before marshaling if v implements BeforeUpdater.
+func (b *UpdateBuilder) Struct(v interface{}, opts ...Option) *UpdateBuilder {
+	v = addressable(v)
+	if err := beforeUpdate(v); err != nil {
+		b.err = err
+		return b
+	}
+
+	columns, values, err := marshalRow(v, newOptions(opts), isPrimaryKey)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	for i, column := range columns {
+		b.Set(column, values[i])
+	}
+
+	return b
+}
+
+// Where appends a condition, multiple conditions are joined with AND
+func (b *UpdateBuilder) Where(cond string, args ...interface{}) *UpdateBuilder {
+	b.where.add(cond, args...)
+	return b
+}
+
+// Build returns the sql statement and its arguments
+func (b *UpdateBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if b.table == "" {
+		return "", nil, errNoTable
+	}
+
+	if len(b.columns) == 0 {
+		return "", nil, errNoColumns
+	}
+
+	var sb strings.Builder
+	sb.WriteString("UPDATE ")
+	sb.WriteString(b.table)
+	sb.WriteString(" SET ")
+	for i, column := range b.columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(column + " = ?")
+	}
+
+	args := append([]interface{}{}, b.values...)
+	args = b.where.build(&sb, args)
+	return Rebind(b.dialect, sb.String()), args, nil
+}
+
+// DeleteBuilder builds a DELETE statement
+type DeleteBuilder struct {
+	table   string
+	where   conditions
+	dialect Dialect
+}
+
+// Delete returns a DeleteBuilder which deletes from the given table
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{
+		table: table,
+	}
+}
+
+// Dialect sets the Dialect of the statement, MySQL is used by default
+func (b *DeleteBuilder) Dialect(d Dialect) *DeleteBuilder {
+	b.dialect = d
+	return b
+}
+
+// Where appends a condition, multiple conditions are joined with AND
+func (b *DeleteBuilder) Where(cond string, args ...interface{}) *DeleteBuilder {
+	b.where.add(cond, args...)
+	return b
+}
+
+// Build returns the sql statement and its arguments
+func (b *DeleteBuilder) Build() (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, errNoTable
+	}
+
+	var sb strings.Builder
+	sb.WriteString("DELETE FROM ")
+	sb.WriteString(b.table)
+	args := b.where.build(&sb, nil)
+	return Rebind(b.dialect, sb.String()), args, nil
+}
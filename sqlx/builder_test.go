@@ -0,0 +1,176 @@
+package sqlx
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumns(t *testing.T) {
+	type Bar struct {
+		IdNumber string `db:"id_number"`
+		Gender   string `db:"gender,size=10"`
+	}
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+		Age  int
+		*Bar
+	}
+
+	expected := []string{"id", "name", "Age", "id_number", "gender"}
+	assert.Equal(t, expected, mustColumns(t, Foo{}))
+	assert.Equal(t, expected, mustColumns(t, &Foo{}))
+	assert.Equal(t, expected, mustColumns(t, (*Foo)(nil)))
+
+	_, err := Columns(1)
+	assert.Equal(t, errNotStruct, err)
+	_, err = Columns(nil)
+	assert.Equal(t, errNotStruct, err)
+
+	type Baz struct {
+		Id   int64  `db:"id"`
+		name string `db:"name"`
+	}
+	_, err = Columns(Baz{})
+	assert.Equal(t, errNotSettable, err)
+}
+
+func mustColumns(t *testing.T, v interface{}, opts ...Option) []string {
+	columns, err := Columns(v, opts...)
+	assert.Nil(t, err)
+	return columns
+}
+
+func TestSelectBuilder(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		query, args, err := Select("id", "name").From("user").Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT id, name FROM user", query)
+		assert.Nil(t, args)
+	})
+
+	t.Run("full", func(t *testing.T) {
+		query, args, err := Select("u.id", "o.amount").
+			From("user u").
+			LeftJoin("orders o", "o.user_id = u.id AND o.status = ?", 1).
+			Where("u.age > ?", 18).
+			Where("u.name = ? OR u.name = ?", "foo", "bar").
+			OrderBy("u.id DESC", "o.amount").
+			Limit(10).
+			Offset(20).
+			Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT u.id, o.amount FROM user u LEFT JOIN orders o ON o.user_id = u.id AND o.status = ? "+
+			"WHERE (u.age > ?) AND (u.name = ? OR u.name = ?) ORDER BY u.id DESC, o.amount LIMIT 10 OFFSET 20", query)
+		assert.Equal(t, []interface{}{1, 18, "foo", "bar"}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := Select().From("user").Build()
+		assert.Equal(t, errNoColumns, err)
+
+		_, _, err = Select("id").Build()
+		assert.Equal(t, errNoTable, err)
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+
+		type Foo struct {
+			Id   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+
+		query, args, err := Select(mustColumns(t, Foo{})...).From("user").Where("id = ?", 1).Build()
+		assert.Nil(t, err)
+
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(rs)
+		rows, err := db.Query(query, args...)
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, Foo{Id: 1, Name: "test"}, foo)
+	})
+}
+
+func TestInsertBuilder(t *testing.T) {
+	t.Run("multiple rows", func(t *testing.T) {
+		query, args, err := Insert("user").Columns("id", "name").Values(1, "foo").Values(2, "bar").Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "INSERT INTO user (id, name) VALUES (?, ?), (?, ?)", query)
+		assert.Equal(t, []interface{}{1, "foo", 2, "bar"}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := Insert("").Columns("id").Values(1).Build()
+		assert.Equal(t, errNoTable, err)
+
+		_, _, err = Insert("user").Values(1).Build()
+		assert.Equal(t, errNoColumns, err)
+
+		_, _, err = Insert("user").Columns("id").Build()
+		assert.NotNil(t, err)
+
+		_, _, err = Insert("user").Columns("id", "name").Values(1).Build()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestUpdateBuilder(t *testing.T) {
+	query, args, err := Update("user").Set("name", "foo").Set("age", 20).Where("id = ?", 1).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET name = ?, age = ? WHERE id = ?", query)
+	assert.Equal(t, []interface{}{"foo", 20, 1}, args)
+
+	_, _, err = Update("user").Where("id = ?", 1).Build()
+	assert.Equal(t, errNoColumns, err)
+
+	type Foo struct {
+		Id   int64  `db:"id,pk"`
+		Name string `db:"name"`
+	}
+
+	query, args, err = Update("user").Struct(Foo{Id: 3, Name: "foo"}).Where("id = ?", 3).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET name = ? WHERE id = ?", query)
+	assert.Equal(t, []interface{}{"foo", 3}, args)
+
+	query, _, err = Insert("user").Struct(Foo{Id: 3, Name: "foo"}).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO user (id, name) VALUES (?, ?)", query)
+
+	type Bar struct {
+		UserId   int64 `db:",pk"`
+		NickName string
+	}
+
+	query, args, err = Update("user").Struct(Bar{UserId: 3, NickName: "bar"}, WithNameMapper(SnakeCaseMapper)).
+		Where("user_id = ?", 3).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET nick_name = ? WHERE user_id = ?", query)
+	assert.Equal(t, []interface{}{"bar", 3}, args)
+
+	query, _, err = Insert("user").Struct(Bar{UserId: 3, NickName: "bar"}, WithNameMapper(SnakeCaseMapper)).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO user (user_id, nick_name) VALUES (?, ?)", query)
+}
+
+func TestDeleteBuilder(t *testing.T) {
+	query, args, err := Delete("user").Where("id = ?", 1).Where("age < ?", 18).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM user WHERE (id = ?) AND (age < ?)", query)
+	assert.Equal(t, []interface{}{1, 18}, args)
+
+	query, args, err = Delete("user").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM user", query)
+	assert.Nil(t, args)
+}
@@ -0,0 +1,142 @@
+package sqlx
+
+import (
+	"reflect"
+	"sync"
+)
+
+// structCache caches the field layout of struct types, map[reflect.Type]*structInfo
+var structCache sync.Map
+
+// fieldInfo describes a struct field which can be scanned into
+type fieldInfo struct {
+	name string
+	// path is the dotted path of the field, such as "Bar.IdNumber"
+	path  string
+	index []int
+	// parts holds the name parts of the column name, see columnName
+	parts []namePart
+	tag   Tag
+}
+
+// structInfo describes the field layout of a struct type, the fields of
+// anonymous structs are expanded.
+type structInfo struct {
+	fields  []*fieldInfo
+	columns map[string]*fieldInfo
+	err     error
+}
+
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	if v, ok := structCache.Load(t); ok {
+		info := v.(*structInfo)
+		return info, info.err
+	}
+
+	info := buildStructInfo(t)
+	v, _ := structCache.LoadOrStore(t, info)
+	info = v.(*structInfo)
+	return info, info.err
+}
+
+// clearStructCache removes the cached struct layouts, which are rebuilt on next use
+func clearStructCache() {
+	structCache.Range(func(key, _ interface{}) bool {
+		structCache.Delete(key)
+		return true
+	})
+}
+
+func buildStructInfo(t reflect.Type) *structInfo {
+	info := &structInfo{
+		columns: make(map[string]*fieldInfo),
+	}
+	info.err = info.walk(t, nil, "", nil)
+	return info
+}
+
+func (s *structInfo) walk(t reflect.Type, parent []int, parentPath string, prefix []namePart) error {
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		tag := ParseTag(ft)
+		ignored, err := ignoreField(ft, tag)
+		if err != nil {
+			return err
+		}
+
+		if ignored {
+			continue
+		}
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+		path := parentPath + ft.Name
+
+		fvt := indirect(ft.Type)
+		separator, prefixed := getPrefix(ft, tag)
+		if prefixed || isEmbedded(ft, tag) {
+			parts := prefix
+			if prefixed {
+				parts = appendPart(prefix, namePart{name: tag.Name, fieldName: ft.Name, separator: separator})
+			}
+
+			if err := s.walk(fvt, index, path+".", parts); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		field := &fieldInfo{
+			path:  path,
+			index: index,
+			parts: appendPart(prefix, namePart{name: tag.Name, fieldName: ft.Name}),
+			tag:   tag,
+		}
+		field.name = field.columnName(IdentityMapper)
+		s.fields = append(s.fields, field)
+		s.columns[field.name] = field
+	}
+
+	return nil
+}
+
+func appendPart(parts []namePart, part namePart) []namePart {
+	list := make([]namePart, len(parts)+1)
+	copy(list, parts)
+	list[len(parts)] = part
+	return list
+}
+
+// fieldByIndex returns the nested field of v by index, the nil pointer structs
+// on the way will be allocated.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// readFieldByIndex is like fieldByIndex but never allocates, it reports false
+// if there is a nil pointer struct on the way.
+func readFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
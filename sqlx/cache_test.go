@@ -0,0 +1,263 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type BenchBar struct {
+	IdNumber string `db:"id_number"`
+	Gender   string `db:"gender"`
+}
+
+type benchFoo struct {
+	Id    int64   `db:"id"`
+	Name  string  `db:"name"`
+	Age   int     `db:"age"`
+	Score float64 `db:"score"`
+	*BenchBar
+}
+
+var benchColumns = []string{"id", "name", "age", "score", "id_number", "gender"}
+
+func TestGetStructInfo(t *testing.T) {
+	t.Run("layout", func(t *testing.T) {
+		type Baz struct {
+			Nickname string `db:"nickname"`
+		}
+
+		type Bar struct {
+			IdNumber string `db:"id_number"`
+			*Baz
+		}
+
+		type Foo struct {
+			Id int64 `db:"id"`
+			*Bar
+		}
+
+		info, err := getStructInfo(reflect.TypeOf(Foo{}))
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0}, info.columns["id"].index)
+		assert.Equal(t, []int{1, 0}, info.columns["id_number"].index)
+		assert.Equal(t, []int{1, 1, 0}, info.columns["nickname"].index)
+
+		cached, err := getStructInfo(reflect.TypeOf(Foo{}))
+		assert.Nil(t, err)
+		assert.True(t, info == cached)
+	})
+
+	t.Run("not settable", func(t *testing.T) {
+		type Foo struct {
+			Id   int64  `db:"id"`
+			name string `db:"name"`
+		}
+
+		_, err := getStructInfo(reflect.TypeOf(Foo{}))
+		assert.Equal(t, errNotSettable, err)
+		_, err = getStructInfo(reflect.TypeOf(Foo{}))
+		assert.Equal(t, errNotSettable, err)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		type base struct {
+			CreatedAt string `db:"created_at"`
+		}
+
+		type Foo struct {
+			Id       int64 `db:"id"`
+			mu       sync.Mutex
+			cache    map[string]string
+			Callback func()
+			Extra    interface{}
+			Tags     map[string]string
+			Value    interface{} `db:"value"`
+			*strings.Builder
+			base
+		}
+
+		info, err := getStructInfo(reflect.TypeOf(Foo{}))
+		assert.Nil(t, err)
+		var paths []string
+		for _, field := range info.fields {
+			paths = append(paths, field.path)
+		}
+		assert.Equal(t, []string{"Id", "Value", "base.CreatedAt"}, paths)
+		assert.Equal(t, []string{"id", "value", "created_at"}, mustColumns(t, Foo{}))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		type Foo struct {
+			Id int64 `db:"id"`
+		}
+
+		var wg sync.WaitGroup
+		infos := make([]*structInfo, 10)
+		for i := 0; i < len(infos); i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				infos[i], _ = getStructInfo(reflect.TypeOf(Foo{}))
+			}(i)
+		}
+		wg.Wait()
+
+		for _, info := range infos {
+			assert.True(t, infos[0] == info)
+		}
+	})
+}
+
+func TestScanPlan(t *testing.T) {
+	plan, err := newScanPlan(reflect.TypeOf(benchFoo{}), append(benchColumns, "unknown"), newOptions(nil))
+	assert.Nil(t, err)
+
+	var foo benchFoo
+	dest := plan.dest(reflect.ValueOf(&foo).Elem())
+	assert.Len(t, dest, len(benchColumns)+1)
+	assert.NotNil(t, foo.BenchBar)
+	*(dest[0].(*int64)) = 1
+	*(dest[4].(*string)) = "1001"
+	assert.Equal(t, int64(1), foo.Id)
+	assert.Equal(t, "1001", foo.IdNumber)
+
+	plan, err = newScanPlan(reflect.TypeOf(benchFoo{}), []string{"id"}, newOptions(nil))
+	assert.Nil(t, err)
+	foo = benchFoo{}
+	plan.dest(reflect.ValueOf(&foo).Elem())
+	assert.Nil(t, foo.BenchBar)
+
+	_, err = newScanPlan(reflect.TypeOf(benchFoo{}), []string{"id"}, newOptions([]Option{WithRequireAllFields()}))
+	assert.NotNil(t, err)
+}
+
+func BenchmarkScanDest(b *testing.B) {
+	t := reflect.TypeOf(benchFoo{})
+	b.Run("baseline getFields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := baselineDest(reflect.New(t), benchColumns); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached plan", func(b *testing.B) {
+		b.ReportAllocs()
+		plan, err := newScanPlan(t, benchColumns, newOptions(nil))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for i := 0; i < b.N; i++ {
+			plan.dest(reflect.New(t).Elem())
+		}
+	})
+}
+
+// baselineDest is the per row reflection which was used to compute the scan
+// destinations before the struct layout was cached, it's kept as the baseline
+// of BenchmarkScanDest.
+func baselineDest(v reflect.Value, columns []string) ([]interface{}, error) {
+	fields, err := baselineFields(v)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]interface{}, 0)
+	for _, column := range columns {
+		if v, ok := fields[column]; ok {
+			list = append(list, v.Interface())
+		} else {
+			var anonymous interface{}
+			list = append(list, &anonymous)
+		}
+	}
+
+	return list, nil
+}
+
+func baselineFields(v reflect.Value) (map[string]reflect.Value, error) {
+	ve := reflect.Indirect(v)
+	vt := indirect(ve.Type())
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < ve.NumField(); i++ {
+		fv := ve.Field(i)
+		ft := vt.Field(i)
+		tag, ok := ft.Tag.Lookup(tagKey)
+		if !ok {
+			tag = ft.Name
+		} else if index := strings.Index(tag, ","); index > 0 {
+			tag = tag[:index]
+		}
+
+		fvt := indirect(fv.Type())
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			if !fv.CanInterface() {
+				return nil, errNotSettable
+			}
+
+			fv.Set(reflect.New(indirect(fv.Type())))
+		}
+
+		if fvt.Kind() == reflect.Struct && ft.Anonymous {
+			fds, err := baselineFields(fv)
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range fds {
+				fields[k] = v
+			}
+		} else {
+			if !fv.CanAddr() || !fv.Addr().CanInterface() {
+				return nil, errNotSettable
+			}
+
+			fields[tag] = fv.Addr()
+		}
+	}
+
+	return fields, nil
+}
+
+func BenchmarkUnmarshalRows(b *testing.B) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	const n = 1000
+	values := make([][]driver.Value, n)
+	for i := range values {
+		values[i] = []driver.Value{int64(i), "test" + strconv.Itoa(i), int64(20), 89.5, "1001", "男"}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rs := mock.NewRows(benchColumns)
+		for _, v := range values {
+			rs.AddRow(v...)
+		}
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		var foo []benchFoo
+		if err := UnmarshalRows(rows, &foo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sqlx
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// converters holds the registered Converter of each type
+var converters sync.Map
+
+// Converter converts a value returned by the driver, such as int64, float64,
+// bool, []byte, string, time.Time and nil, into a value of the registered type.
+type Converter func(src interface{}) (interface{}, error)
+
+// RegisterConverter registers a Converter for the type of sample, such as
+// time.Duration(0), the struct fields and the basic-type values of that type,
+// or the pointer of that type, are scanned by fn instead of the conversions
+// of rows.Scan. It's expected to be called at startup, the previous Converter
+// of the same type will be replaced. The cached struct layouts are cleared, so
+// that a struct type which is expanded before becomes a single column.
+func RegisterConverter(sample interface{}, fn Converter) {
+	converters.Store(reflect.TypeOf(sample), fn)
+	clearStructCache()
+}
+
+func getConverter(t reflect.Type) (Converter, bool) {
+	fn, ok := converters.Load(t)
+	if !ok {
+		return nil, false
+	}
+
+	return fn.(Converter), true
+}
+
+// converterScanner scans a column into a value by the registered Converter,
+// a NULL column is scanned into a pointer as nil.
+type converterScanner struct {
+	value   reflect.Value
+	convert Converter
+}
+
+// Scan implements sql.Scanner
+func (s *converterScanner) Scan(src interface{}) error {
+	v := s.value
+	if v.Kind() == reflect.Ptr {
+		if src == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	result, err := s.convert(src)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(result)
+	switch {
+	case !rv.IsValid():
+		v.Set(reflect.Zero(v.Type()))
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case rv.Type().ConvertibleTo(v.Type()):
+		v.Set(rv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("can not convert %s into %s", rv.Type(), v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStatus int32
+
+const (
+	testStatusUnknown testStatus = iota
+	testStatusActive
+)
+
+type testMoney struct {
+	Cents int64
+}
+
+func init() {
+	RegisterConverter(time.Duration(0), func(src interface{}) (interface{}, error) {
+		n, ok := src.(int64)
+		if !ok {
+			return nil, errors.New("expected int64")
+		}
+
+		return time.Duration(n), nil
+	})
+	RegisterConverter(testStatus(0), func(src interface{}) (interface{}, error) {
+		if src == nil {
+			return testStatusUnknown, nil
+		}
+
+		if string(src.([]byte)) == "active" {
+			return testStatusActive, nil
+		}
+
+		return testStatusUnknown, nil
+	})
+	RegisterConverter(testMoney{}, func(src interface{}) (interface{}, error) {
+		n, err := strconv.ParseInt(strings.Replace(string(src.([]byte)), ".", "", 1), 10, 64)
+		return testMoney{Cents: n}, err
+	})
+}
+
+func TestRegisterConverter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id      int64          `db:"id"`
+		Timeout time.Duration  `db:"timeout"`
+		Status  testStatus     `db:"status"`
+		Balance testMoney      `db:"balance"`
+		Delay   *time.Duration `db:"delay"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "timeout", "status", "balance", "delay"}).
+			AddRow(1, int64(time.Second), []byte("active"), []byte("10.25"), int64(time.Minute)).
+			AddRow(2, int64(0), nil, []byte("0.00"), nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithStrict())
+		assert.Nil(t, err)
+		delay := time.Minute
+		assert.Equal(t, []Foo{
+			{Id: 1, Timeout: time.Second, Status: testStatusActive, Balance: testMoney{Cents: 1025}, Delay: &delay},
+			{Id: 2},
+		}, foo)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		rs := mock.NewRows([]string{"balance"}).AddRow([]byte("1.50"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select balance from user")
+		assert.Nil(t, err)
+
+		var balance testMoney
+		err = UnmarshalRow(rows, &balance)
+		assert.Nil(t, err)
+		assert.Equal(t, testMoney{Cents: 150}, balance)
+
+		rs = mock.NewRows([]string{"timeout"}).AddRow(int64(time.Second)).AddRow(int64(time.Minute))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select timeout from user")
+		assert.Nil(t, err)
+
+		var timeouts []time.Duration
+		err = UnmarshalRows(rows, &timeouts)
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Duration{time.Second, time.Minute}, timeouts)
+	})
+
+	t.Run("registered after use", func(t *testing.T) {
+		type Point struct {
+			X int `db:"x"`
+			Y int `db:"y"`
+		}
+
+		type Bar struct {
+			Id int64 `db:"id"`
+			Point
+		}
+
+		assert.Equal(t, []string{"id", "x", "y"}, mustColumns(t, Bar{}))
+
+		RegisterConverter(Point{}, func(src interface{}) (interface{}, error) {
+			var p Point
+			_, err := fmt.Sscanf(string(src.([]byte)), "(%d,%d)", &p.X, &p.Y)
+			return p, err
+		})
+		defer func() {
+			converters.Delete(reflect.TypeOf(Point{}))
+			clearStructCache()
+		}()
+
+		assert.Equal(t, []string{"id", "Point"}, mustColumns(t, Bar{}))
+
+		rs := mock.NewRows([]string{"id", "Point"}).AddRow(1, []byte("(1,2)"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var bar Bar
+		err = UnmarshalRow(rows, &bar, WithStrict())
+		assert.Nil(t, err)
+		assert.Equal(t, Bar{Id: 1, Point: Point{X: 1, Y: 2}}, bar)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"timeout"}).AddRow("1s")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select timeout from user")
+		assert.Nil(t, err)
+
+		var timeout time.Duration
+		err = UnmarshalRow(rows, &timeout)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,252 @@
+package sqlx
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	// MySQL is the Dialect of MySQL
+	MySQL Dialect = mysqlDialect{}
+	// PostgreSQL is the Dialect of PostgreSQL
+	PostgreSQL Dialect = postgresDialect{}
+	// SQLite is the Dialect of SQLite
+	SQLite Dialect = sqliteDialect{}
+
+	defaultDialect = MySQL
+)
+
+// Dialect describes the syntax differences between databases
+type Dialect interface {
+	// Placeholder returns the placeholder of the n-th argument, n starts from 1.
+	Placeholder(n int) string
+	// Quote quotes an identifier, the dotted identifier like user.id is quoted
+	// part by part.
+	Quote(identifier string) string
+	// Upsert returns the clause appended to an INSERT statement, which updates
+	// the columns if there is a conflict on the conflict columns, and does
+	// nothing if there are no columns to update.
+	Upsert(conflictColumns, updateColumns []string) string
+	// LimitOffset returns the LIMIT and OFFSET clause, the non-positive limit
+	// and offset are ignored.
+	LimitOffset(limit, offset int) string
+}
+
+// Rebind rewrites the ? placeholders of query into the placeholders of the
+// Dialect, the contents of quoted strings and identifiers are left untouched,
+// which are recognized by the quoting rules of the Dialect.
+func Rebind(d Dialect, query string) string {
+	d = dialectOrDefault(d)
+
+	var sb strings.Builder
+	var n int
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if isQuote(c) {
+			end := skipQuoted(d, query, i)
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		if c != '?' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		n++
+		sb.WriteString(d.Placeholder(n))
+	}
+
+	return sb.String()
+}
+
+func dialectOrDefault(d Dialect) Dialect {
+	if d == nil {
+		return defaultDialect
+	}
+
+	return d
+}
+
+// skipQuoted returns the index after the closing quote of the quoted string
+// or identifier starting at i, a doubled quote is treated as an escaped quote.
+// A backslash escapes the next character in the strings quoted by ' and " of
+// MySQL, and in the escape strings like E'it\'s' of PostgreSQL, it's a normal
+// character in the other strings and the identifiers, d can be nil for the
+// standard SQL.
+func skipQuoted(d Dialect, query string, i int) int {
+	quote := query[i]
+	backslash := escapesBackslash(d, query, i)
+	for j := i + 1; j < len(query); j++ {
+		if backslash && query[j] == '\\' {
+			j++
+			continue
+		}
+
+		if query[j] != quote {
+			continue
+		}
+
+		if j+1 < len(query) && query[j+1] == quote {
+			j++
+			continue
+		}
+
+		return j + 1
+	}
+
+	return len(query)
+}
+
+// escapesBackslash reports whether a backslash is an escape character in the
+// quoted string starting at i.
+func escapesBackslash(d Dialect, query string, i int) bool {
+	quote := query[i]
+	if quote == '`' {
+		return false
+	}
+
+	if _, ok := d.(mysqlDialect); ok {
+		return true
+	}
+
+	// the escape string constants of PostgreSQL, such as E'\n'
+	return quote == '\'' && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') &&
+		(i == 1 || !isNameChar(query[i-2]))
+}
+
+func quote(identifier string, q string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = q + strings.ReplaceAll(part, q, q+q) + q
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func limitOffset(limit, offset int, noLimit string) string {
+	var clauses []string
+	if limit > 0 {
+		clauses = append(clauses, "LIMIT "+strconv.Itoa(limit))
+	} else if offset > 0 && noLimit != "" {
+		clauses = append(clauses, "LIMIT "+noLimit)
+	}
+
+	if offset > 0 {
+		clauses = append(clauses, "OFFSET "+strconv.Itoa(offset))
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+// usesOnConflict reports whether d upserts in the form of ON CONFLICT, which
+// requires the conflict columns to do updates.
+func usesOnConflict(d Dialect) bool {
+	switch d.(type) {
+	case postgresDialect, sqliteDialect:
+		return true
+	default:
+		return false
+	}
+}
+
+// onConflict returns the upsert clause in the form of ON CONFLICT, which is
+// supported by both PostgreSQL and SQLite.
+func onConflict(d Dialect, conflictColumns, updateColumns []string) string {
+	quoted := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		quoted[i] = d.Quote(column)
+	}
+
+	clause := "ON CONFLICT"
+	if len(quoted) > 0 {
+		clause += " (" + strings.Join(quoted, ", ") + ")"
+	}
+
+	if len(updateColumns) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = d.Quote(column) + " = EXCLUDED." + d.Quote(column)
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(sets, ", ")
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (mysqlDialect) Quote(identifier string) string {
+	return quote(identifier, "`")
+}
+
+func (d mysqlDialect) Upsert(conflictColumns, updateColumns []string) string {
+	// MySQL detects the conflicts by all unique keys, an assignment of
+	// the column itself is used to do nothing on conflicts.
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return ""
+		}
+
+		column := d.Quote(conflictColumns[0])
+		return "ON DUPLICATE KEY UPDATE " + column + " = " + column
+	}
+
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = d.Quote(column) + " = VALUES(" + d.Quote(column) + ")"
+	}
+
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+func (mysqlDialect) LimitOffset(limit, offset int) string {
+	// MySQL doesn't support OFFSET without LIMIT
+	return limitOffset(limit, offset, "18446744073709551615")
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (postgresDialect) Quote(identifier string) string {
+	return quote(identifier, `"`)
+}
+
+func (d postgresDialect) Upsert(conflictColumns, updateColumns []string) string {
+	return onConflict(d, conflictColumns, updateColumns)
+}
+
+func (postgresDialect) LimitOffset(limit, offset int) string {
+	return limitOffset(limit, offset, "")
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (sqliteDialect) Quote(identifier string) string {
+	return quote(identifier, `"`)
+}
+
+func (d sqliteDialect) Upsert(conflictColumns, updateColumns []string) string {
+	return onConflict(d, conflictColumns, updateColumns)
+}
+
+func (sqliteDialect) LimitOffset(limit, offset int) string {
+	// SQLite doesn't support OFFSET without LIMIT
+	return limitOffset(limit, offset, "-1")
+}
@@ -0,0 +1,118 @@
+package sqlx
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	query := "SELECT * FROM user WHERE id = ? AND name <> '?' AND age > ?"
+	assert.Equal(t, query, Rebind(MySQL, query))
+	assert.Equal(t, query, Rebind(SQLite, query))
+	assert.Equal(t, query, Rebind(nil, query))
+	assert.Equal(t, "SELECT * FROM user WHERE id = $1 AND name <> '?' AND age > $2", Rebind(PostgreSQL, query))
+	assert.Equal(t, `SELECT 'C:\' WHERE id = $1`, Rebind(PostgreSQL, `SELECT 'C:\' WHERE id = ?`))
+	assert.Equal(t, `SELECT "a\" WHERE id = $1`, Rebind(PostgreSQL, `SELECT "a\" WHERE id = ?`))
+	assert.Equal(t, `SELECT E'it\'s ?' WHERE id = $1`, Rebind(PostgreSQL, `SELECT E'it\'s ?' WHERE id = ?`))
+	assert.Equal(t, `SELECT 'C:\' WHERE id = ?`, Rebind(SQLite, `SELECT 'C:\' WHERE id = ?`))
+}
+
+func TestDialect(t *testing.T) {
+	t.Run("quote", func(t *testing.T) {
+		assert.Equal(t, "`user`.`id`", MySQL.Quote("user.id"))
+		assert.Equal(t, "`a``b`", MySQL.Quote("a`b"))
+		assert.Equal(t, `"user".*`, PostgreSQL.Quote("user.*"))
+		assert.Equal(t, `"a""b"`, SQLite.Quote(`a"b`))
+	})
+
+	t.Run("limit offset", func(t *testing.T) {
+		assert.Equal(t, "LIMIT 10 OFFSET 20", MySQL.LimitOffset(10, 20))
+		assert.Equal(t, "LIMIT 18446744073709551615 OFFSET 20", MySQL.LimitOffset(0, 20))
+		assert.Equal(t, "LIMIT 10", PostgreSQL.LimitOffset(10, 0))
+		assert.Equal(t, "OFFSET 20", PostgreSQL.LimitOffset(0, 20))
+		assert.Equal(t, "LIMIT -1 OFFSET 20", SQLite.LimitOffset(0, 20))
+		assert.Equal(t, "", SQLite.LimitOffset(0, 0))
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		assert.Equal(t, "ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `age` = VALUES(`age`)",
+			MySQL.Upsert([]string{"id"}, []string{"name", "age"}))
+		assert.Equal(t, "ON DUPLICATE KEY UPDATE `id` = `id`", MySQL.Upsert([]string{"id"}, nil))
+		assert.Equal(t, `ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "age" = EXCLUDED."age"`,
+			PostgreSQL.Upsert([]string{"id"}, []string{"name", "age"}))
+		assert.Equal(t, `ON CONFLICT ("id") DO NOTHING`, SQLite.Upsert([]string{"id"}, nil))
+		assert.Equal(t, `ON CONFLICT DO NOTHING`, PostgreSQL.Upsert(nil, nil))
+	})
+}
+
+func TestBuilderDialect(t *testing.T) {
+	t.Run("select", func(t *testing.T) {
+		query, args, err := Select("id", "name").From("user").Where("id > ?", 1).Where("age < ?", 18).
+			Limit(10).Offset(20).Dialect(PostgreSQL).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT id, name FROM user WHERE (id > $1) AND (age < $2) LIMIT 10 OFFSET 20", query)
+		assert.Equal(t, []interface{}{1, 18}, args)
+
+		query, _, err = Select("id").From("user").Offset(20).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT id FROM user LIMIT 18446744073709551615 OFFSET 20", query)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		query, args, err := Insert("user").Columns("id", "name").Values(1, "test").
+			Upsert([]string{"id"}, "name").Dialect(PostgreSQL).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, `INSERT INTO user (id, name) VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`, query)
+		assert.Equal(t, []interface{}{1, "test"}, args)
+
+		query, _, err = Insert("user").Columns("id", "name").Values(1, "test").Upsert([]string{"id"}, "name").Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "INSERT INTO user (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", query)
+
+		query, _, err = Insert("user").Columns("id", "name").Values(1, "test").Upsert(nil).Dialect(SQLite).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "INSERT INTO user (id, name) VALUES (?, ?) ON CONFLICT DO NOTHING", query)
+
+		_, _, err = Insert("user").Columns("id", "name").Values(1, "test").Upsert(nil, "name").Dialect(PostgreSQL).Build()
+		assert.Equal(t, errNoConflictColumns, err)
+
+		_, _, err = Insert("user").Columns("id", "name").Values(1, "test").Upsert(nil, "name").Dialect(SQLite).Build()
+		assert.Equal(t, errNoConflictColumns, err)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		query, _, err := Update("user").Set("name", "test").Where("id = ?", 1).Dialect(PostgreSQL).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "UPDATE user SET name = $1 WHERE id = $2", query)
+
+		query, _, err = Delete("user").Where("id = ?", 1).Dialect(SQLite).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "DELETE FROM user WHERE id = ?", query)
+	})
+
+	t.Run("sqlmock", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+
+		type Foo struct {
+			Id   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+
+		query, args, err := In("select id,name from user where id in (?) and name <> ?", []int{1, 2}, "test")
+		assert.Nil(t, err)
+		query = Rebind(PostgreSQL, query)
+		assert.Equal(t, "select id,name from user where id in ($1, $2) and name <> $3", query)
+
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,foo\n2,bar")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1, 2, "test").WillReturnRows(rs)
+
+		var foo []Foo
+		err = NewSession(db).QueryRows(&foo, query, args...)
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Name: "foo"}, {Id: 2, Name: "bar"}}, foo)
+	})
+}
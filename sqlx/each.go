@@ -0,0 +1,107 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrBreak can be returned or wrapped by the callback of UnmarshalEach to
+	// stop the iteration, it will not be returned by UnmarshalEach.
+	ErrBreak = errors.New("break")
+
+	errInvalidCallback = errors.New("expected a callback like func(v *T) error or func(v T) error")
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// UnmarshalEach scans the rows one by one and calls fn with each of them, instead
+// of appending all rows into a slice like UnmarshalRows does, fn must be like
+// func(v *T) error or func(v T) error, T can be any type supported by UnmarshalRows.
+// The iteration stops at the first error returned by fn, which will be returned
+// except ErrBreak, the error of rows will be returned after the last row, the
+// caller is responsible for closing the rows.
+func UnmarshalEach(rows *sql.Rows, fn interface{}, opts ...Option) error {
+	if fn == nil {
+		return errInvalidCallback
+	}
+
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.NumOut() != 1 || ft.Out(0) != errorType {
+		return errInvalidCallback
+	}
+
+	item := ft.In(0)
+	itemBaseType := indirect(item)
+	scan, err := newValueScanner(rows, itemBaseType, newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		value, err := scan()
+		if err != nil {
+			return err
+		}
+
+		if item.Kind() != reflect.Ptr {
+			value = value.Elem()
+		}
+
+		out := fv.Call([]reflect.Value{value})
+		if err, ok := out[0].Interface().(error); ok && err != nil {
+			if errors.Is(err, ErrBreak) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// newValueScanner returns a function which scans the current row into a new
+// value of type t, and returns a pointer of it.
+func newValueScanner(rows *sql.Rows, t reflect.Type, o *options) (func() (reflect.Value, error), error) {
+	if isMap(t) {
+		scanner, err := newMapScanner(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (reflect.Value, error) {
+			m, err := scanner.scan(rows, t)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			value := reflect.New(t)
+			value.Elem().Set(m)
+			return value, afterScan(value.Interface())
+		}, nil
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner, err := newRowScanner(t, columns, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var row int
+	return func() (reflect.Value, error) {
+		value := reflect.New(t)
+		if err := scanRow(rows, scanner.dest(value.Elem()), row, columns, t, scanner.plan); err != nil {
+			return reflect.Value{}, err
+		}
+
+		row++
+
+		return value, afterScan(value.Interface())
+	}, nil
+}
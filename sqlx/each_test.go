@@ -0,0 +1,140 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalEach(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var foo []*Foo
+		err = UnmarshalEach(rows, func(v *Foo) error {
+			foo = append(foo, v)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []*Foo{{Id: 1, Name: "test1"}, {Id: 2, Name: "test2"}}, foo)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id"}).FromCSVString("1\n2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id from user")
+		assert.Nil(t, err)
+
+		var sum int
+		err = UnmarshalEach(rows, func(v int) error {
+			sum += v
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, sum)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id"}).AddRow(int64(1))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id from user")
+		assert.Nil(t, err)
+
+		var list []map[string]interface{}
+		err = UnmarshalEach(rows, func(v map[string]interface{}) error {
+			list = append(list, v)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []map[string]interface{}{{"id": int64(1)}}, list)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2\n3,test3")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var ids []int64
+		err = UnmarshalEach(rows, func(v Foo) error {
+			ids = append(ids, v.Id)
+			if v.Id == 2 {
+				return ErrBreak
+			}
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{1, 2}, ids)
+	})
+
+	t.Run("wrapped break", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var count int
+		err = UnmarshalEach(rows, func(v *Foo) error {
+			count++
+			return fmt.Errorf("stop at %d: %w", v.Id, ErrBreak)
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		fnErr := errors.New("fn error")
+		var count int
+		err = UnmarshalEach(rows, func(v *Foo) error {
+			count++
+			return fnErr
+		})
+		assert.Equal(t, fnErr, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		rowErr := errors.New("row error")
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2").RowError(1, rowErr)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var count int
+		err = UnmarshalEach(rows, func(v *Foo) error {
+			count++
+			return nil
+		})
+		assert.Equal(t, rowErr, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("invalid callback", func(t *testing.T) {
+		assert.Equal(t, errInvalidCallback, UnmarshalEach(nil, nil))
+		assert.Equal(t, errInvalidCallback, UnmarshalEach(nil, 1))
+		assert.Equal(t, errInvalidCallback, UnmarshalEach(nil, func(v *Foo) {}))
+		assert.Equal(t, errInvalidCallback, UnmarshalEach(nil, func(v *Foo, i int) error { return nil }))
+	})
+}
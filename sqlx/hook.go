@@ -0,0 +1,72 @@
+package sqlx
+
+import "reflect"
+
+// AfterScanner is implemented by the models which need to do something after
+// being scanned, such as decoding derived fields and validating invariants,
+// AfterScan is called by UnmarshalRow, UnmarshalRows and the other functions
+// scanning rows after each value is populated, the scanning stops at the
+// first error returned by AfterScan.
+type AfterScanner interface {
+	AfterScan() error
+}
+
+// BeforeInserter is implemented by the models which need to do something
+// before being inserted, BeforeInsert is called by InsertBuilder.Struct before
+// the model is marshaled, the error returned by it is returned by Build. The
+// model passed by value is copied, the hook is called on the copy and the copy
+// is marshaled.
+type BeforeInserter interface {
+	BeforeInsert() error
+}
+
+// BeforeUpdater is implemented by the models which need to do something
+// before being updated, BeforeUpdate is called by UpdateBuilder.Struct before
+// the model is marshaled, the error returned by it is returned by Build. The
+// model passed by value is copied like BeforeInserter.
+type BeforeUpdater interface {
+	BeforeUpdate() error
+}
+
+// afterScan calls the AfterScan of v if it's implemented, v is a pointer of
+// the scanned value.
+func afterScan(v interface{}) error {
+	if scanner, ok := v.(AfterScanner); ok {
+		return scanner.AfterScan()
+	}
+
+	return nil
+}
+
+// addressable returns v if it's a pointer, or a pointer of a copy of v, so that
+// the hooks with pointer receivers can be called on a value passed by value.
+func addressable(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		return v
+	}
+
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr.Interface()
+}
+
+func beforeInsert(v interface{}) error {
+	if inserter, ok := v.(BeforeInserter); ok {
+		return inserter.BeforeInsert()
+	}
+
+	return nil
+}
+
+func beforeUpdate(v interface{}) error {
+	if updater, ok := v.(BeforeUpdater); ok {
+		return updater.BeforeUpdate()
+	}
+
+	return nil
+}
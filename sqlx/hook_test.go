@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type hookUser struct {
+	Id        int64  `db:"id"`
+	Name      string `db:"name"`
+	Version   int64  `db:"version"`
+	Upper     string `db:"-"`
+	UpdatedBy string `db:"updated_by,omitempty"`
+}
+
+func (u *hookUser) AfterScan() error {
+	if u.Name == "" {
+		return errors.New("empty name")
+	}
+
+	u.Upper = strings.ToUpper(u.Name)
+	return nil
+}
+
+func (u *hookUser) BeforeInsert() error {
+	u.Version = 1
+	return nil
+}
+
+func (u *hookUser) BeforeUpdate() error {
+	if u.Id == 0 {
+		return errors.New("no id")
+	}
+
+	u.UpdatedBy = "admin"
+	return nil
+}
+
+func TestAfterScan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	t.Run("row", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,foo")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var user hookUser
+		err = UnmarshalRow(rows, &user)
+		assert.Nil(t, err)
+		assert.Equal(t, hookUser{Id: 1, Name: "foo", Upper: "FOO"}, user)
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,foo\n2,bar")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var users []*hookUser
+		err = UnmarshalRows(rows, &users)
+		assert.Nil(t, err)
+		assert.Equal(t, []*hookUser{{Id: 1, Name: "foo", Upper: "FOO"}, {Id: 2, Name: "bar", Upper: "BAR"}}, users)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,foo")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var users map[int64]hookUser
+		err = UnmarshalRowsToMap(rows, &users, "id")
+		assert.Nil(t, err)
+		assert.Equal(t, map[int64]hookUser{1: {Id: 1, Name: "foo", Upper: "FOO"}}, users)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,foo\n2,")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var users []hookUser
+		err = UnmarshalRows(rows, &users)
+		assert.EqualError(t, err, "empty name")
+	})
+}
+
+func TestBeforeHooks(t *testing.T) {
+	query, args, err := Insert("user").Struct(&hookUser{Name: "foo"}).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO user (id, name, version) VALUES (?, ?, ?)", query)
+	assert.Equal(t, []interface{}{int64(0), "foo", int64(1)}, args)
+
+	query, args, err = Update("user").Struct(&hookUser{Id: 1, Name: "foo"}).Where("id = ?", 1).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET id = ?, name = ?, version = ?, updated_by = ? WHERE id = ?", query)
+	assert.Equal(t, []interface{}{int64(1), "foo", int64(0), "admin", 1}, args)
+
+	_, _, err = Update("user").Struct(&hookUser{Name: "foo"}).Build()
+	assert.EqualError(t, err, "no id")
+
+	t.Run("by value", func(t *testing.T) {
+		user := hookUser{Name: "foo"}
+		query, args, err := Insert("user").Struct(user).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "INSERT INTO user (id, name, version) VALUES (?, ?, ?)", query)
+		assert.Equal(t, []interface{}{int64(0), "foo", int64(1)}, args)
+		assert.Equal(t, int64(0), user.Version)
+
+		_, _, err = Update("user").Struct(hookUser{Name: "x"}).Build()
+		assert.EqualError(t, err, "no id")
+
+		query, args, err = Update("user").Struct(hookUser{Id: 1, Name: "foo"}).Where("id = ?", 1).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "UPDATE user SET id = ?, name = ?, version = ?, updated_by = ? WHERE id = ?", query)
+		assert.Equal(t, []interface{}{int64(1), "foo", int64(0), "admin", 1}, args)
+	})
+}
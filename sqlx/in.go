@@ -0,0 +1,185 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	errEmptySlice = errors.New("empty slice passed to in query")
+
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// In expands the slice arguments into multiple placeholders, so that a query
+// like "SELECT * FROM user WHERE id IN (?)" with a []int64 argument could be
+// executed, both the ? and the $n placeholder styles are supported, and the $n
+// placeholders are renumbered after expanding. []byte and the implementations
+// of driver.Valuer are not expanded. The quoted strings are recognized by the
+// standard SQL quoting rules, use InDialect for the rules of a Dialect.
+func In(query string, args ...interface{}) (string, []interface{}, error) {
+	return InDialect(nil, query, args...)
+}
+
+// InDialect is like In, but the quoted strings of query are recognized by the
+// quoting rules of d, such as the backslash escapes of MySQL.
+func InDialect(d Dialect, query string, args ...interface{}) (string, []interface{}, error) {
+	values := make([][]interface{}, len(args))
+	var expand bool
+	for i, arg := range args {
+		list, ok := asSlice(arg)
+		if !ok {
+			values[i] = []interface{}{arg}
+			continue
+		}
+
+		if len(list) == 0 {
+			return "", nil, errEmptySlice
+		}
+
+		values[i] = list
+		expand = true
+	}
+
+	if !expand {
+		return query, args, nil
+	}
+
+	var newArgs []interface{}
+	for _, list := range values {
+		newArgs = append(newArgs, list...)
+	}
+
+	if hasDollarPlaceholder(d, query) {
+		newQuery, err := expandDollar(d, query, values)
+		return newQuery, newArgs, err
+	}
+
+	newQuery, err := expandQuestion(d, query, values)
+	return newQuery, newArgs, err
+}
+
+func asSlice(arg interface{}) ([]interface{}, bool) {
+	if arg == nil {
+		return nil, false
+	}
+
+	v := reflect.ValueOf(arg)
+	t := v.Type()
+	if t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 || t.Implements(valuerType) {
+		return nil, false
+	}
+
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+
+	return list, true
+}
+
+func hasDollarPlaceholder(d Dialect, query string) bool {
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if isQuote(c) {
+			i = skipQuoted(d, query, i) - 1
+			continue
+		}
+
+		if c == '$' && i+1 < len(query) && isDigit(query[i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func expandQuestion(d Dialect, query string, values [][]interface{}) (string, error) {
+	var sb strings.Builder
+	var n int
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if isQuote(c) {
+			end := skipQuoted(d, query, i)
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		if c != '?' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if n >= len(values) {
+			return "", fmt.Errorf("expected argument num %d, but found %d", n+1, len(values))
+		}
+
+		sb.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(values[n])), ", "))
+		n++
+	}
+
+	if n != len(values) {
+		return "", fmt.Errorf("expected argument num %d, but found %d", n, len(values))
+	}
+
+	return sb.String(), nil
+}
+
+func expandDollar(d Dialect, query string, values [][]interface{}) (string, error) {
+	// starts holds the new position of each argument
+	starts := make([]int, len(values))
+	position := 1
+	for i, list := range values {
+		starts[i] = position
+		position += len(list)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if isQuote(c) {
+			end := skipQuoted(d, query, i)
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		if c != '$' || i+1 >= len(query) || !isDigit(query[i+1]) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		end := i + 1
+		for end < len(query) && isDigit(query[end]) {
+			end++
+		}
+
+		n, err := strconv.Atoi(query[i+1 : end])
+		if err != nil {
+			return "", err
+		}
+
+		if n < 1 || n > len(values) {
+			return "", fmt.Errorf("placeholder $%d out of range, found %d arguments", n, len(values))
+		}
+
+		for j := range values[n-1] {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("$" + strconv.Itoa(starts[n-1]+j))
+		}
+		i = end - 1
+	}
+
+	return sb.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
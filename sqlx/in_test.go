@@ -0,0 +1,86 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIn(t *testing.T) {
+	t.Run("question", func(t *testing.T) {
+		query, args, err := In("SELECT * FROM user WHERE id IN (?) AND name = ? AND gender IN (?)",
+			[]int64{1, 2, 3}, "test", []string{"男"})
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id IN (?, ?, ?) AND name = ? AND gender IN (?)", query)
+		assert.Equal(t, []interface{}{int64(1), int64(2), int64(3), "test", "男"}, args)
+	})
+
+	t.Run("dollar", func(t *testing.T) {
+		query, args, err := In("SELECT * FROM user WHERE name = $1 AND id = ANY(ARRAY[$2]) AND '$3' <> $3 OR nickname = $1",
+			"test", []int{1, 2}, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE name = $1 AND id = ANY(ARRAY[$2, $3]) AND '$3' <> $4 OR nickname = $1", query)
+		assert.Equal(t, []interface{}{"test", 1, 2, 3}, args)
+	})
+
+	t.Run("not expanded", func(t *testing.T) {
+		query, args, err := In("UPDATE user SET avatar = ?, name = ? WHERE id = ?",
+			[]byte("avatar"), sql.NullString{String: "test", Valid: true}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "UPDATE user SET avatar = ?, name = ? WHERE id = ?", query)
+		assert.Equal(t, []interface{}{[]byte("avatar"), sql.NullString{String: "test", Valid: true}, 1}, args)
+	})
+
+	t.Run("quoted", func(t *testing.T) {
+		query, args, err := In("SELECT * FROM user WHERE name <> '?' AND id IN (?)", []int{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE name <> '?' AND id IN (?, ?)", query)
+		assert.Equal(t, []interface{}{1, 2}, args)
+
+		query, args, err = InDialect(MySQL, `SELECT * FROM user WHERE name <> 'it\'s ?' AND id IN (?)`, []int{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT * FROM user WHERE name <> 'it\'s ?' AND id IN (?, ?)`, query)
+		assert.Equal(t, []interface{}{1, 2}, args)
+
+		query, args, err = In(`SELECT * FROM user WHERE path = 'C:\' AND id IN ($1)`, []int{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT * FROM user WHERE path = 'C:\' AND id IN ($1, $2)`, query)
+		assert.Equal(t, []interface{}{1, 2}, args)
+
+		query, args, err = InDialect(PostgreSQL, `SELECT * FROM user WHERE path = 'C:\' AND id IN (?)`, []int{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT * FROM user WHERE path = 'C:\' AND id IN (?, ?)`, query)
+		assert.Equal(t, []interface{}{1, 2}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := In("SELECT * FROM user WHERE id IN (?)", []int{})
+		assert.Equal(t, errEmptySlice, err)
+
+		_, _, err = In("SELECT * FROM user WHERE id IN (?)", []int{1}, 2)
+		assert.EqualError(t, err, "expected argument num 1, but found 2")
+
+		_, _, err = In("SELECT * FROM user WHERE id IN (?) AND age > ?", []int{1})
+		assert.EqualError(t, err, "expected argument num 2, but found 1")
+
+		_, _, err = In("SELECT * FROM user WHERE id IN ($2)", []int{1})
+		assert.EqualError(t, err, "placeholder $2 out of range, found 1 arguments")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+
+		query, args, err := In("select id from user where id in (?)", []int64{1, 2})
+		assert.Nil(t, err)
+		rs := mock.NewRows([]string{"id"}).FromCSVString("1\n2")
+		mock.ExpectQuery(`select id from user where id in \(\?, \?\)`).WithArgs(int64(1), int64(2)).WillReturnRows(rs)
+
+		var ids []int64
+		err = NewSession(db).QueryRows(&ids, query, args...)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{1, 2}, ids)
+	})
+}
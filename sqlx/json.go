@@ -0,0 +1,50 @@
+package sqlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonScanner decodes a json column into a value of any type, such as a
+// struct, a map or a slice, NULL is decoded as the zero value.
+type jsonScanner struct {
+	value reflect.Value
+}
+
+// Scan implements sql.Scanner
+func (s *jsonScanner) Scan(src interface{}) error {
+	s.value.Set(reflect.Zero(s.value.Type()))
+
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("can not decode json from %T", src)
+	}
+
+	return json.Unmarshal(data, s.value.Addr().Interface())
+}
+
+// jsonValue encodes v into a json string, the nil pointer, map, slice and
+// interface are encoded as NULL.
+func jsonValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
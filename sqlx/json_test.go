@@ -0,0 +1,70 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Settings struct {
+		Theme string `json:"theme"`
+	}
+
+	type Foo struct {
+		Id       int64             `db:"id"`
+		Settings Settings          `db:"settings,json"`
+		Tags     []string          `db:"tags,json"`
+		Extra    map[string]string `db:"extra,json"`
+		Profile  *Settings         `db:"profile,json"`
+	}
+
+	columns := []string{"id", "settings", "tags", "extra", "profile"}
+
+	t.Run("unmarshal", func(t *testing.T) {
+		rs := mock.NewRows(columns).
+			AddRow(1, []byte(`{"theme":"dark"}`), `["a","b"]`, `{"k":"v"}`, nil).
+			AddRow(2, nil, nil, nil, `{"theme":"light"}`)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithStrict())
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{
+			{Id: 1, Settings: Settings{Theme: "dark"}, Tags: []string{"a", "b"}, Extra: map[string]string{"k": "v"}},
+			{Id: 2, Profile: &Settings{Theme: "light"}},
+		}, foo)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rs := mock.NewRows(columns).AddRow(1, `{`, nil, nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		foo := Foo{Id: 1, Settings: Settings{Theme: "dark"}, Tags: []string{"a"}}
+		assert.Equal(t, columns, mustColumns(t, foo))
+
+		names, values, err := MarshalRow(foo)
+		assert.Nil(t, err)
+		assert.Equal(t, columns, names)
+		assert.Equal(t, []interface{}{int64(1), `{"theme":"dark"}`, `["a"]`, nil, nil}, values)
+
+		query, args, err := BindNamed("update user set settings = :settings where id = :id", foo)
+		assert.Nil(t, err)
+		assert.Equal(t, "update user set settings = ? where id = ?", query)
+		assert.Equal(t, []interface{}{`{"theme":"dark"}`, int64(1)}, args)
+	})
+}
@@ -0,0 +1,165 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errNotMap = errors.New("expected a pointer of map")
+
+// UnmarshalRowsToMap accepts an interface which type must be a pointer of map[K]V or
+// map[K][]V, the rows will be keyed by the column named key, or by the column of
+// the struct field whose path is key, such as "Id" and "Bar.IdNumber". V can be a
+// struct, which is scanned like UnmarshalRows does, or a scalar, in that case the
+// rows must have two columns, one for the key and the other for the value.
+// The rows with the same key will be grouped into map[K][]V, and the last one
+// wins in map[K]V. The error of rows is returned after the last row.
+func UnmarshalRowsToMap(rows *sql.Rows, v interface{}, key string, opts ...Option) error {
+	if err := must(v); err != nil {
+		return err
+	}
+
+	mapv := reflect.Indirect(reflect.ValueOf(v))
+	mapt := mapv.Type()
+	if mapt.Kind() != reflect.Map {
+		return errNotMap
+	}
+
+	if !mapv.CanSet() {
+		return errNotSettable
+	}
+
+	elem := mapt.Elem()
+	grouped := elem.Kind() == reflect.Slice && !isScalar(elem)
+	item := elem
+	if grouped {
+		item = elem.Elem()
+	}
+	itemBaseType := indirect(item)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	o := newOptions(opts)
+	scanner, err := newRowScanner(itemBaseType, columns, o)
+	if err != nil {
+		return err
+	}
+
+	keyIndex, err := findKeyColumn(itemBaseType, columns, key, o)
+	if err != nil {
+		return err
+	}
+
+	if scanner.plan == nil && len(columns) != 2 {
+		return fmt.Errorf("expected column num 2, but found %d", len(columns))
+	}
+
+	if mapv.IsNil() {
+		mapv.Set(reflect.MakeMap(mapt))
+	}
+
+	keyType := mapt.Key()
+	for row := 0; rows.Next(); row++ {
+		value := reflect.New(itemBaseType)
+		var keyValue reflect.Value
+		var list []interface{}
+		if scanner.plan == nil {
+			keyValue = reflect.New(keyType).Elem()
+			list = make([]interface{}, 2)
+			list[keyIndex] = keyValue.Addr().Interface()
+			list[1-keyIndex] = scanner.dest(value.Elem())[0]
+		} else {
+			list = scanner.dest(value.Elem())
+			// the key column which is not mapped to any field is scanned
+			// into the key directly.
+			if scanner.plan.fields[keyIndex] == nil {
+				keyValue = reflect.New(keyType).Elem()
+				list[keyIndex] = keyValue.Addr().Interface()
+			}
+		}
+
+		if err := scanRow(rows, list, row, columns, itemBaseType, scanner.plan); err != nil {
+			return err
+		}
+
+		if err := afterScan(value.Interface()); err != nil {
+			return err
+		}
+
+		if !keyValue.IsValid() {
+			keyValue, err = convertKey(fieldByIndex(value.Elem(), scanner.plan.fields[keyIndex].index), keyType)
+			if err != nil {
+				return err
+			}
+		}
+
+		if item.Kind() != reflect.Ptr {
+			value = value.Elem()
+		}
+
+		if grouped {
+			list := mapv.MapIndex(keyValue)
+			if !list.IsValid() {
+				list = reflect.MakeSlice(elem, 0, 1)
+			}
+			value = reflect.Append(list, value)
+		}
+
+		mapv.SetMapIndex(keyValue, value)
+	}
+
+	return rows.Err()
+}
+
+// findKeyColumn returns the index of the key column, key is a column name or
+// the path of a field of struct type t.
+func findKeyColumn(t reflect.Type, columns []string, key string, o *options) (int, error) {
+	name := key
+	if t.Kind() == reflect.Struct && !isScalar(t) {
+		info, err := getStructInfo(t)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, ok := info.columnMap(o)[o.columnKey(key)]; !ok {
+			for _, field := range info.fields {
+				if field.path == key {
+					name = field.columnName(o.nameMapper)
+					break
+				}
+			}
+		}
+	}
+
+	for i, column := range columns {
+		if o.columnKey(column) == o.columnKey(name) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("key column %q not found", key)
+}
+
+func convertKey(v reflect.Value, keyType reflect.Type) (reflect.Value, error) {
+	if v.Kind() == reflect.Ptr && !v.Type().AssignableTo(keyType) {
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("key is nil")
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type().AssignableTo(keyType):
+		return v, nil
+	case v.Type().ConvertibleTo(keyType):
+		return v.Convert(keyType), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("can not convert key from %s to %s", v.Type(), keyType)
+	}
+}
@@ -0,0 +1,136 @@
+package sqlx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalRowsToMap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id     int64  `db:"id"`
+		Name   string `db:"name"`
+		Gender string `db:"gender"`
+	}
+
+	t.Run("struct by column", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "gender"}).FromCSVString("1,test1,男\n2,test2,女")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[int64]Foo
+		err = UnmarshalRowsToMap(rows, &m, "id")
+		assert.Nil(t, err)
+		assert.Equal(t, map[int64]Foo{
+			1: {Id: 1, Name: "test1", Gender: "男"},
+			2: {Id: 2, Name: "test2", Gender: "女"},
+		}, m)
+	})
+
+	t.Run("struct by field", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "gender"}).FromCSVString("1,test1,男\n2,test2,女")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[string]*Foo
+		err = UnmarshalRowsToMap(rows, &m, "Name")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]*Foo{
+			"test1": {Id: 1, Name: "test1", Gender: "男"},
+			"test2": {Id: 2, Name: "test2", Gender: "女"},
+		}, m)
+	})
+
+	t.Run("grouped", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "gender"}).FromCSVString("1,test1,男\n2,test2,女\n3,test3,男")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		m := map[string][]Foo{
+			"未知": {},
+		}
+		err = UnmarshalRowsToMap(rows, &m, "gender")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string][]Foo{
+			"未知": {},
+			"男":  {{Id: 1, Name: "test1", Gender: "男"}, {Id: 3, Name: "test3", Gender: "男"}},
+			"女":  {{Id: 2, Name: "test2", Gender: "女"}},
+		}, m)
+	})
+
+	t.Run("unmapped key column", func(t *testing.T) {
+		rs := mock.NewRows([]string{"group_id", "id", "name"}).FromCSVString("10,1,test1\n10,2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[int][]*Foo
+		err = UnmarshalRowsToMap(rows, &m, "group_id")
+		assert.Nil(t, err)
+		assert.Equal(t, map[int][]*Foo{
+			10: {{Id: 1, Name: "test1"}, {Id: 2, Name: "test2"}},
+		}, m)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var m map[int64]string
+		err = UnmarshalRowsToMap(rows, &m, "id")
+		assert.Nil(t, err)
+		assert.Equal(t, map[int64]string{1: "test1", 2: "test2"}, m)
+	})
+
+	t.Run("scalar grouped", func(t *testing.T) {
+		rs := mock.NewRows([]string{"name", "gender"}).FromCSVString("test1,男\ntest2,女\ntest3,男")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select name,gender from user")
+		assert.Nil(t, err)
+
+		var m map[string][]string
+		err = UnmarshalRowsToMap(rows, &m, "gender")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string][]string{"男": {"test1", "test3"}, "女": {"test2"}}, m)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "gender"}).FromCSVString("1,test1,男")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[int64]Foo
+		err = UnmarshalRowsToMap(rows, &m, "age")
+		assert.EqualError(t, err, `key column "age" not found`)
+
+		var s map[int64]string
+		err = UnmarshalRowsToMap(rows, &s, "id")
+		assert.EqualError(t, err, "expected column num 2, but found 3")
+
+		var list []Foo
+		err = UnmarshalRowsToMap(rows, &list, "id")
+		assert.Equal(t, errNotMap, err)
+	})
+	t.Run("rows error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "gender"}).FromCSVString("1,test1,男\n2,test2,女").
+			RowError(1, errors.New("boom"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[int64]Foo
+		err = UnmarshalRowsToMap(rows, &m, "id")
+		assert.EqualError(t, err, "boom")
+	})
+}
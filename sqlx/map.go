@@ -0,0 +1,109 @@
+package sqlx
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+)
+
+// textualTypes are the database type names whose []byte values will be
+// converted into string, the types contain CHAR or TEXT are textual too.
+var textualTypes = map[string]struct{}{
+	"ENUM":   {},
+	"SET":    {},
+	"JSON":   {},
+	"JSONB":  {},
+	"UUID":   {},
+	"NAME":   {},
+	"CITEXT": {},
+}
+
+// isMap reports whether t is a map[string]interface{}
+func isMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		t.Elem().Kind() == reflect.Interface && t.Elem().NumMethod() == 0
+}
+
+// mapScanner scans the rows of a result set into map[string]interface{}
+type mapScanner struct {
+	columns []string
+	textual []bool
+	// row is the index of the current row
+	row int
+}
+
+func newMapScanner(rows *sql.Rows) (*mapScanner, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := &mapScanner{
+		columns: make([]string, len(columnTypes)),
+		textual: make([]bool, len(columnTypes)),
+	}
+	for i, columnType := range columnTypes {
+		scanner.columns[i] = columnType.Name()
+		scanner.textual[i] = isTextual(columnType)
+	}
+
+	return scanner, nil
+}
+
+func isTextual(columnType *sql.ColumnType) bool {
+	name := strings.ToUpper(columnType.DatabaseTypeName())
+	if strings.Contains(name, "CHAR") || strings.Contains(name, "TEXT") {
+		return true
+	}
+
+	_, ok := textualTypes[name]
+	return ok
+}
+
+// scan scans the current row into a new map of type t
+func (s *mapScanner) scan(rows *sql.Rows, t reflect.Type) (reflect.Value, error) {
+	values := make([]interface{}, len(s.columns))
+	list := make([]interface{}, len(s.columns))
+	for i := range values {
+		list[i] = &values[i]
+	}
+
+	if err := scanRow(rows, list, s.row, s.columns, t.Elem(), nil); err != nil {
+		return reflect.Value{}, err
+	}
+	s.row++
+
+	m := reflect.MakeMapWithSize(t, len(s.columns))
+	for i, column := range s.columns {
+		value := values[i]
+		if b, ok := value.([]byte); ok && s.textual[i] {
+			value = string(b)
+		}
+
+		// the key might be a named string type, such as map[Column]interface{}
+		key := reflect.ValueOf(column).Convert(t.Key())
+		if value == nil {
+			m.SetMapIndex(key, reflect.Zero(t.Elem()))
+		} else {
+			m.SetMapIndex(key, reflect.ValueOf(value))
+		}
+	}
+
+	return m, nil
+}
+
+func scanMapRow(rows *sql.Rows, v interface{}) error {
+	scanner, err := newMapScanner(rows)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(v))
+	m, err := scanner.scan(rows, value.Type())
+	if err != nil {
+		return err
+	}
+
+	value.Set(m)
+	return nil
+}
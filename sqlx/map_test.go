@@ -0,0 +1,92 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalMap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	newRows := func() *sqlmock.Rows {
+		return mock.NewRowsWithColumnDefinition(
+			mock.NewColumn("id").OfType("BIGINT", int64(0)),
+			mock.NewColumn("name").OfType("VARCHAR", ""),
+			mock.NewColumn("avatar").OfType("BLOB", []byte(nil)),
+			mock.NewColumn("nickname").OfType("TEXT", ""),
+		)
+	}
+
+	t.Run("row", func(t *testing.T) {
+		rs := newRows().AddRow(int64(1), []byte("test"), []byte("avatar"), nil)
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
+		rows, err := db.Query("select * from user where id = ?", 1)
+		assert.Nil(t, err)
+
+		var m map[string]interface{}
+		err = UnmarshalRow(rows, &m)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"id":       int64(1),
+			"name":     "test",
+			"avatar":   []byte("avatar"),
+			"nickname": nil,
+		}, m)
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		rs := newRows().AddRow(int64(1), []byte("test1"), nil, []byte("foo")).
+			AddRow(int64(2), []byte("test2"), nil, []byte("bar"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var list []map[string]interface{}
+		err = UnmarshalRows(rows, &list)
+		assert.Nil(t, err)
+		assert.Equal(t, []map[string]interface{}{
+			{"id": int64(1), "name": "test1", "avatar": nil, "nickname": "foo"},
+			{"id": int64(2), "name": "test2", "avatar": nil, "nickname": "bar"},
+		}, list)
+	})
+
+	t.Run("rows pointer", func(t *testing.T) {
+		type Row map[string]interface{}
+		rs := newRows().AddRow(int64(1), []byte("test1"), nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var list []*Row
+		err = UnmarshalRows(rows, &list)
+		assert.Nil(t, err)
+		assert.Equal(t, []*Row{{"id": int64(1), "name": "test1", "avatar": nil, "nickname": nil}}, list)
+	})
+
+	t.Run("named key", func(t *testing.T) {
+		type Column string
+		rs := newRows().AddRow(int64(1), []byte("test1"), nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[Column]interface{}
+		err = UnmarshalRow(rows, &m)
+		assert.Nil(t, err)
+		assert.Equal(t, map[Column]interface{}{"id": int64(1), "name": "test1", "avatar": nil, "nickname": nil}, m)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		rs := newRows().AddRow(int64(1), []byte("test1"), nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[string]string
+		err = UnmarshalRow(rows, &m)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package sqlx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMapper maps the name of a struct field without db tag into a column name,
+// the field name is used as it is by default, which can be changed by the
+// Option WithNameMapper.
+type NameMapper func(name string) string
+
+// IdentityMapper uses the field name as the column name
+func IdentityMapper(name string) string {
+	return name
+}
+
+// LowerCaseMapper maps the field name into lower case, such as CreatedAt into createdat
+func LowerCaseMapper(name string) string {
+	return strings.ToLower(name)
+}
+
+// SnakeCaseMapper maps the field name into snake case, such as CreatedAt into
+// created_at, and UserID into user_id. A single lower case letter following an
+// initialism belongs to it if it's a plural s or followed by a digit, such as
+// UserIDs into user_ids, and IPv4Addr into ipv4_addr.
+func SnakeCaseMapper(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			sb.WriteRune(r)
+			continue
+		}
+
+		if i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && nextLower && !endsInitialism(runes, i+1) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
+
+// endsInitialism reports whether the lower case letter at i is the end of the
+// initialism before it, which is a single letter as a plural s or followed by
+// a digit.
+func endsInitialism(runes []rune, i int) bool {
+	next := i + 1
+	if next < len(runes) && unicode.IsLower(runes[next]) {
+		return false
+	}
+
+	return runes[i] == 's' || next < len(runes) && unicode.IsDigit(runes[next])
+}
+
+// namePart is a part of the column name of a field, the column name is joined
+// by the names of the prefixed structs and the name of the field itself.
+type namePart struct {
+	// name is the name in db tag, it's empty if there is no db tag
+	name      string
+	fieldName string
+	separator string
+}
+
+// mapName returns the name in db tag, or the field name mapped by mapper if
+// there is no db tag.
+func (p namePart) mapName(mapper NameMapper) string {
+	if p.name != "" {
+		return p.name
+	}
+
+	if mapper == nil {
+		return p.fieldName
+	}
+
+	return mapper(p.fieldName)
+}
+
+// columnName returns the column name of the field mapped by mapper
+func (f *fieldInfo) columnName(mapper NameMapper) string {
+	if mapper == nil {
+		return f.name
+	}
+
+	var sb strings.Builder
+	for _, part := range f.parts {
+		sb.WriteString(part.mapName(mapper))
+		sb.WriteString(part.separator)
+	}
+
+	return sb.String()
+}
+
+// columnMap returns the fields keyed by column names which are mapped by the
+// NameMapper of o, and in lower case if o is case-insensitive.
+func (s *structInfo) columnMap(o *options) map[string]*fieldInfo {
+	if o.nameMapper == nil && !o.caseInsensitive {
+		return s.columns
+	}
+
+	columns := make(map[string]*fieldInfo, len(s.fields))
+	for _, field := range s.fields {
+		columns[o.columnKey(field.columnName(o.nameMapper))] = field
+	}
+
+	return columns
+}
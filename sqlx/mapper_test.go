@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCaseMapper(t *testing.T) {
+	cases := map[string]string{
+		"Id":         "id",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Address2":   "address2",
+		"IPv4Addr":   "ipv4_addr",
+		"UserIDs":    "user_ids",
+		"IDsMap":     "ids_map",
+		"HTTPStatus": "http_status",
+		"IsAOk":      "is_a_ok",
+		"name":       "name",
+	}
+	for name, expected := range cases {
+		assert.Equal(t, expected, SnakeCaseMapper(name), name)
+	}
+
+	assert.Equal(t, "createdat", LowerCaseMapper("CreatedAt"))
+	assert.Equal(t, "CreatedAt", IdentityMapper("CreatedAt"))
+}
+
+func TestWithNameMapper(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Address struct {
+		City string
+	}
+
+	type Foo struct {
+		UserID    int64
+		Name      string `db:"nick"`
+		CreatedAt string
+		Address   Address `db:"home,prefix=_"`
+	}
+
+	t.Run("unmarshal", func(t *testing.T) {
+		rs := mock.NewRows([]string{"user_id", "nick", "created_at", "home_city"}).FromCSVString("1,test,today,beijing")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select user_id,nick,created_at,home_city from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithNameMapper(SnakeCaseMapper), WithStrict())
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{UserID: 1, Name: "test", CreatedAt: "today", Address: Address{City: "beijing"}}}, foo)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		rs := mock.NewRows([]string{"UserID", "created_at"}).FromCSVString("1,today")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select UserID,created_at from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, Foo{UserID: 1}, foo)
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		SetDefaultOptions(WithNameMapper(SnakeCaseMapper))
+		defer SetDefaultOptions()
+
+		assert.Equal(t, []string{"user_id", "nick", "created_at", "home_city"}, mustColumns(t, Foo{}))
+
+		columns, values, err := MarshalRow(Foo{UserID: 1, Name: "test"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"user_id", "nick", "created_at", "home_city"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "test", "", ""}, values)
+
+		query, args, err := BindNamed("select * from user where user_id = :user_id and nick = :nick", Foo{UserID: 1, Name: "test"})
+		assert.Nil(t, err)
+		assert.Equal(t, "select * from user where user_id = ? and nick = ?", query)
+		assert.Equal(t, []interface{}{int64(1), "test"}, args)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		rs := mock.NewRows([]string{"user_id", "nick"}).FromCSVString("1,test")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select user_id,nick from user")
+		assert.Nil(t, err)
+
+		var m map[int64]Foo
+		err = UnmarshalRowsToMap(rows, &m, "UserID", WithNameMapper(SnakeCaseMapper))
+		assert.Nil(t, err)
+		assert.Equal(t, map[int64]Foo{1: {UserID: 1, Name: "test"}}, m)
+	})
+}
+
+func TestWithCaseInsensitive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+		Age  int
+	}
+
+	rs := mock.NewRows([]string{"ID", "Name", "age"}).FromCSVString("1,test,20")
+	mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+	rows, err := db.Query("select ID,Name,age from user")
+	assert.Nil(t, err)
+
+	var foo Foo
+	err = UnmarshalRow(rows, &foo, WithCaseInsensitive(), WithStrict())
+	assert.Nil(t, err)
+	assert.Equal(t, Foo{Id: 1, Name: "test", Age: 20}, foo)
+
+	query, args, err := BindNamed("select * from user where id = :ID", foo, WithCaseInsensitive())
+	assert.Nil(t, err)
+	assert.Equal(t, "select * from user where id = ?", query)
+	assert.Equal(t, []interface{}{int64(1)}, args)
+}
@@ -0,0 +1,64 @@
+package sqlx
+
+import (
+	"errors"
+	"reflect"
+)
+
+var errNotStruct = errors.New("expected a struct or a pointer of struct")
+
+// MarshalRow is the inverse of UnmarshalRow, it walks the fields of a struct in
+// declaration order and returns the column names and values, the fields of
+// anonymous structs and the struct fields tagged with prefix will be expanded
+// like UnmarshalRow does. A field tagged with `db:"-"` will be skipped, a field
+// tagged with omitempty or auto will be skipped if it has a zero value, a field
+// tagged with readonly is always skipped, the fields of a nil pointer struct
+// will be skipped too. A field tagged with json is encoded as a json string.
+// The names of the fields without db tag are mapped by the NameMapper of opts.
+func MarshalRow(v interface{}, opts ...Option) ([]string, []interface{}, error) {
+	return marshalRow(v, newOptions(opts), nil)
+}
+
+// marshalRow is like MarshalRow, and the fields whose tag is reported true by
+// omit are skipped too.
+func marshalRow(v interface{}, o *options, omit func(tag Tag) bool) ([]string, []interface{}, error) {
+	if v == nil {
+		return nil, nil, errNotStruct
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, nil, errInvalidPointer
+	}
+
+	value = reflect.Indirect(value)
+	if value.Kind() != reflect.Struct {
+		return nil, nil, errNotStruct
+	}
+
+	info, err := getStructInfo(value.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var columns []string
+	var values []interface{}
+	for _, field := range info.fields {
+		fv, ok := readFieldByIndex(value, field.index)
+		if !ok || field.tag.omitted(fv) || omit != nil && omit(field.tag) {
+			continue
+		}
+
+		v := fv.Interface()
+		if field.tag.JSON {
+			if v, err = jsonValue(fv); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		columns = append(columns, field.columnName(o.nameMapper))
+		values = append(values, v)
+	}
+
+	return columns, values, nil
+}
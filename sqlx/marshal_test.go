@@ -0,0 +1,158 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalRow(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		type Foo struct {
+			Id   int64  `db:"id"`
+			Name string `db:"name"`
+			Age  int
+		}
+
+		columns, values, err := MarshalRow(Foo{Id: 1, Name: "test", Age: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "name", "Age"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "test", 20}, values)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		type Bar struct {
+			IdNumber string `db:"id_number"`
+			Gender   string `db:"gender"`
+		}
+
+		type Baz struct {
+			Nickname string `db:"nickname"`
+		}
+
+		type Foo struct {
+			Id int64 `db:"id"`
+			Bar
+			*Baz
+		}
+
+		columns, values, err := MarshalRow(&Foo{Id: 1, Bar: Bar{IdNumber: "1001", Gender: "男"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "id_number", "gender"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "1001", "男"}, values)
+
+		columns, values, err = MarshalRow(&Foo{Id: 1, Baz: &Baz{Nickname: "test"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "id_number", "gender", "nickname"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "", "", "test"}, values)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		type Foo struct {
+			Id      int64  `db:"id,omitempty"`
+			Name    string `db:"name"`
+			Ignored string `db:"-"`
+		}
+
+		columns, values, err := MarshalRow(Foo{Name: "test", Ignored: "ignored"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"name"}, columns)
+		assert.Equal(t, []interface{}{"test"}, values)
+
+		columns, values, err = MarshalRow(Foo{Id: 1, Name: "test"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "name"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "test"}, values)
+	})
+
+	t.Run("readonly and auto", func(t *testing.T) {
+		type Foo struct {
+			Id        int64  `db:"id,pk,auto"`
+			Name      string `db:"name"`
+			UpdatedAt string `db:"updated_at,readonly"`
+			Nickname  string `db:",omitempty"`
+		}
+
+		columns, values, err := MarshalRow(Foo{Name: "test", UpdatedAt: "today"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"name"}, columns)
+		assert.Equal(t, []interface{}{"test"}, values)
+
+		columns, values, err = MarshalRow(Foo{Id: 1, Name: "test", Nickname: "foo"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "name", "Nickname"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "test", "foo"}, values)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		type Address struct {
+			City   string `db:"city"`
+			Street string `db:"street,omitempty"`
+		}
+
+		type Foo struct {
+			Id      int64    `db:"id"`
+			Address Address  `db:"addr,prefix=_"`
+			Company *Address `db:"company,prefix"`
+		}
+
+		columns, values, err := MarshalRow(Foo{Id: 1, Address: Address{City: "beijing"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "addr_city"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "beijing"}, values)
+
+		columns, values, err = MarshalRow(Foo{Id: 1, Company: &Address{City: "shanghai", Street: "nanjing road"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "addr_city", "company.city", "company.street"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "", "shanghai", "nanjing road"}, values)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := MarshalRow(nil)
+		assert.Equal(t, errNotStruct, err)
+
+		_, _, err = MarshalRow(1)
+		assert.Equal(t, errNotStruct, err)
+
+		var foo *struct{ Id int }
+		_, _, err = MarshalRow(foo)
+		assert.Equal(t, errInvalidPointer, err)
+	})
+
+	t.Run("builder", func(t *testing.T) {
+		type Foo struct {
+			Id   int64  `db:"id,omitempty"`
+			Name string `db:"name"`
+		}
+
+		query, args, err := Insert("user").Struct(Foo{Name: "foo"}).Struct(Foo{Name: "bar"}).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "INSERT INTO user (name) VALUES (?), (?)", query)
+		assert.Equal(t, []interface{}{"foo", "bar"}, args)
+
+		_, _, err = Insert("user").Struct(Foo{Name: "foo"}).Struct(Foo{Id: 1, Name: "bar"}).Build()
+		assert.NotNil(t, err)
+
+		query, args, err = Update("user").Struct(Foo{Name: "foo"}).Where("id = ?", 1).Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "UPDATE user SET name = ? WHERE id = ?", query)
+		assert.Equal(t, []interface{}{"foo", 1}, args)
+	})
+}
+
+func TestTagOptions(t *testing.T) {
+	opts := tagOptions("omitempty, size=10")
+	assert.True(t, opts.Contains("omitempty"))
+	assert.True(t, opts.Contains("size=10"))
+	assert.False(t, opts.Contains("size"))
+	assert.False(t, tagOptions("").Contains("omitempty"))
+
+	value, ok := opts.Get("size")
+	assert.True(t, ok)
+	assert.Equal(t, "10", value)
+	value, ok = opts.Get("omitempty")
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+	_, ok = opts.Get("prefix")
+	assert.False(t, ok)
+}
@@ -0,0 +1,131 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errNamedArg = errors.New("expected a struct, a pointer of struct or a map with string keys")
+
+// BindNamed rewrites the named parameters like :name in query into the positional
+// placeholder ?, and returns the arguments in order, which are looked up from arg
+// by name. arg can be a map with string keys, or a struct whose fields are named
+// by the db tags like UnmarshalRow does. The casts like ::text, and the contents
+// of quoted strings and identifiers are left untouched, which are recognized by
+// the standard SQL quoting rules, or the rules of the Dialect specified by
+// WithDialect, such as the backslash escapes of MySQL. The names of the struct
+// fields are matched with opts like UnmarshalRow does.
+func BindNamed(query string, arg interface{}, opts ...Option) (string, []interface{}, error) {
+	o := newOptions(opts)
+	lookup, err := newNamedLookup(arg, o)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isQuote(c):
+			end := skipQuoted(o.dialect, query, i)
+			sb.WriteString(query[i:end])
+			i = end
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			sb.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNameChar(query[end]) {
+				end++
+			}
+
+			name := query[i+1 : end]
+			value, ok, err := lookup(name)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if !ok {
+				return "", nil, fmt.Errorf("named parameter %q not found", name)
+			}
+
+			sb.WriteByte('?')
+			args = append(args, value)
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+
+	return sb.String(), args, nil
+}
+
+func newNamedLookup(arg interface{}, o *options) (func(name string) (interface{}, bool, error), error) {
+	if arg == nil {
+		return nil, errNamedArg
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errInvalidPointer
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		return func(name string) (interface{}, bool, error) {
+			value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !value.IsValid() {
+				return nil, false, nil
+			}
+
+			return value.Interface(), true, nil
+		}, nil
+	case v.Kind() == reflect.Struct:
+		info, err := getStructInfo(v.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		columns := info.columnMap(o)
+		return func(name string) (interface{}, bool, error) {
+			field, ok := columns[o.columnKey(name)]
+			if !ok {
+				return nil, false, nil
+			}
+
+			value, ok := readFieldByIndex(v, field.index)
+			if !ok {
+				return nil, true, nil
+			}
+
+			if field.tag.JSON {
+				data, err := jsonValue(value)
+				return data, true, err
+			}
+
+			return value.Interface(), true, nil
+		}, nil
+	default:
+		return nil, errNamedArg
+	}
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9' || c == '.'
+}
+
+func isQuote(c byte) bool {
+	return c == '\'' || c == '"' || c == '`'
+}
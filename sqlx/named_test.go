@@ -0,0 +1,111 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindNamed(t *testing.T) {
+	type Bar struct {
+		IdNumber string `db:"id_number"`
+	}
+
+	type Address struct {
+		City string `db:"city"`
+	}
+
+	type Foo struct {
+		Id      int64  `db:"id"`
+		Name    string `db:"name"`
+		Age     int
+		Address Address `db:"addr,prefix"`
+		*Bar
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		query, args, err := BindNamed("UPDATE user SET name = :name, Age = :Age, city = :addr.city WHERE id = :id",
+			&Foo{Id: 1, Name: "test", Age: 20, Address: Address{City: "beijing"}})
+		assert.Nil(t, err)
+		assert.Equal(t, "UPDATE user SET name = ?, Age = ?, city = ? WHERE id = ?", query)
+		assert.Equal(t, []interface{}{"test", 20, "beijing", int64(1)}, args)
+	})
+
+	t.Run("nil anonymous pointer", func(t *testing.T) {
+		foo := Foo{Id: 1}
+		query, args, err := BindNamed("SELECT * FROM user WHERE id = :id AND id_number = :id_number", foo)
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id = ? AND id_number = ?", query)
+		assert.Equal(t, []interface{}{int64(1), nil}, args)
+		assert.Nil(t, foo.Bar)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		query, args, err := BindNamed("SELECT * FROM user WHERE id = :id OR parent_id = :id", map[string]interface{}{
+			"id": 1,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id = ? OR parent_id = ?", query)
+		assert.Equal(t, []interface{}{1, 1}, args)
+	})
+
+	t.Run("cast and quotes", func(t *testing.T) {
+		query, args, err := BindNamed(`SELECT id::text, ':name', "a:b", 'it''s :name' FROM user WHERE name = :name AND t = '12:00' AND tags[1:2] = '{}'`,
+			map[string]string{"name": "test"})
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT id::text, ':name', "a:b", 'it''s :name' FROM user WHERE name = ? AND t = '12:00' AND tags[1:2] = '{}'`, query)
+		assert.Equal(t, []interface{}{"test"}, args)
+	})
+
+	t.Run("backslash escapes", func(t *testing.T) {
+		query, args, err := BindNamed(`SELECT 'it\'s :x', "say \":x\"", 'C:\\', :id`, map[string]interface{}{"id": 1},
+			WithDialect(MySQL))
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT 'it\'s :x', "say \":x\"", 'C:\\', ?`, query)
+		assert.Equal(t, []interface{}{1}, args)
+
+		query, args, err = BindNamed(`SELECT * FROM file WHERE path = 'C:\' AND id = :id`, map[string]interface{}{"id": 1},
+			WithDialect(PostgreSQL))
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT * FROM file WHERE path = 'C:\' AND id = ?`, query)
+		assert.Equal(t, []interface{}{1}, args)
+
+		query, args, err = BindNamed(`SELECT E'it\'s :x', "a\" FROM file WHERE id = :id`, map[string]interface{}{"id": 1},
+			WithDialect(PostgreSQL))
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT E'it\'s :x', "a\" FROM file WHERE id = ?`, query)
+		assert.Equal(t, []interface{}{1}, args)
+
+		query, args, err = BindNamed(`SELECT * FROM file WHERE path = 'C:\' AND id = :id`, map[string]interface{}{"id": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT * FROM file WHERE path = 'C:\' AND id = ?`, query)
+		assert.Equal(t, []interface{}{1}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := BindNamed("SELECT * FROM user WHERE id = :uid", map[string]interface{}{"id": 1})
+		assert.EqualError(t, err, `named parameter "uid" not found`)
+
+		_, _, err = BindNamed("SELECT * FROM user WHERE id = :id", 1)
+		assert.Equal(t, errNamedArg, err)
+
+		_, _, err = BindNamed("SELECT * FROM user WHERE id = :id", nil)
+		assert.Equal(t, errNamedArg, err)
+
+		_, _, err = BindNamed("SELECT * FROM user WHERE id = :id", (*Foo)(nil))
+		assert.Equal(t, errInvalidPointer, err)
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+
+		query, args, err := BindNamed("UPDATE user SET name = :name WHERE id = :id", Foo{Id: 1, Name: "test"})
+		assert.Nil(t, err)
+		mock.ExpectExec("UPDATE user SET name = (.+) WHERE id = (.+)").WithArgs("test", int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err = NewSession(db).Exec(query, args...)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,130 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// timeLayouts are the layouts to parse the default values of time.Time fields,
+// which are tried in order, the times without zones are parsed in UTC.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// nullScanner scans a nullable column into a non-pointer value through the
+// nullable intermediates like sql.NullInt64, the zero value or the default
+// value is set if the column is NULL.
+type nullScanner struct {
+	value reflect.Value
+	def   string
+}
+
+// isNullable reports whether the NULL of a column is scanned into a value of
+// type t by nullScanner, the pointers, the sql.Scanner implementations, []byte
+// and interface{} can receive NULL by themselves.
+func isNullable(t reflect.Type) bool {
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8,
+		reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Float32,
+		reflect.Float64, reflect.String:
+		return true
+	default:
+		return t == timeType
+	}
+}
+
+// Scan implements sql.Scanner
+func (s *nullScanner) Scan(src interface{}) error {
+	if src != nil {
+		return s.assign(src)
+	}
+
+	if s.def == "" {
+		s.value.Set(reflect.Zero(s.value.Type()))
+		return nil
+	}
+
+	if s.value.Type() == timeType {
+		return s.assignTime(s.def)
+	}
+
+	return s.assign(s.def)
+}
+
+// assignTime sets the time parsed from def by timeLayouts
+func (s *nullScanner) assignTime(def string) error {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, def); err == nil {
+			s.value.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid default time %q, expected layouts %q", def, timeLayouts)
+}
+
+func (s *nullScanner) assign(src interface{}) error {
+	v := s.value
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n sql.NullInt64
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		if v.OverflowInt(n.Int64) {
+			return fmt.Errorf("value %d overflows %s", n.Int64, v.Type())
+		}
+
+		v.SetInt(n.Int64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n sql.NullString
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		u, err := strconv.ParseUint(n.String, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var n sql.NullFloat64
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		v.SetFloat(n.Float64)
+	case reflect.Bool:
+		var n sql.NullBool
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		v.SetBool(n.Bool)
+	case reflect.String:
+		var n sql.NullString
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		v.SetString(n.String)
+	default:
+		var n sql.NullTime
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(n.Time))
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package sqlx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithNullAsZero(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Status string
+
+	type Foo struct {
+		Id        int64     `db:"id"`
+		Name      string    `db:"name"`
+		Age       uint8     `db:"age,default=18"`
+		Score     float64   `db:"score"`
+		Enabled   bool      `db:"enabled,default=true"`
+		Status    Status    `db:"status,default=active"`
+		CreatedAt time.Time `db:"created_at"`
+		Nickname  *string   `db:"nickname"`
+	}
+
+	columns := []string{"id", "name", "age", "score", "enabled", "status", "created_at", "nickname"}
+
+	t.Run("without option", func(t *testing.T) {
+		rs := mock.NewRows(columns).AddRow(1, nil, nil, nil, nil, nil, nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		rs := mock.NewRows(columns).AddRow(1, nil, nil, nil, nil, nil, nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		foo := Foo{Name: "stale"}
+		err = UnmarshalRow(rows, &foo, WithNullAsZero())
+		assert.Nil(t, err)
+		assert.Equal(t, Foo{Id: 1, Age: 18, Enabled: true, Status: "active"}, foo)
+	})
+
+	t.Run("not null", func(t *testing.T) {
+		now := time.Now()
+		rs := mock.NewRows(columns).AddRow(1, []byte("test"), int64(20), "99.5", false, "disabled", now, "foo")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithNullAsZero())
+		assert.Nil(t, err)
+		nickname := "foo"
+		assert.Equal(t, []Foo{{
+			Id:        1,
+			Name:      "test",
+			Age:       20,
+			Score:     99.5,
+			Status:    "disabled",
+			CreatedAt: now,
+			Nickname:  &nickname,
+		}}, foo)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		rs := mock.NewRows(columns).AddRow(1, nil, int64(256), nil, nil, nil, nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo, WithNullAsZero())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("default time", func(t *testing.T) {
+		type Bar struct {
+			CreatedAt time.Time `db:"created_at,default=2020-01-01"`
+			UpdatedAt time.Time `db:"updated_at,default=2020-01-01T08:00:00+08:00"`
+		}
+
+		rs := mock.NewRows([]string{"created_at", "updated_at"}).AddRow(nil, nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var bar Bar
+		err = UnmarshalRow(rows, &bar, WithNullAsZero())
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), bar.CreatedAt)
+		assert.True(t, bar.UpdatedAt.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		type Baz struct {
+			CreatedAt time.Time `db:"created_at,default=yesterday"`
+		}
+
+		rs = mock.NewRows([]string{"created_at"}).AddRow(nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var baz Baz
+		err = UnmarshalRow(rows, &baz, WithNullAsZero())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		rs := mock.NewRows([]string{"age"}).AddRow(nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select age from user")
+		assert.Nil(t, err)
+
+		age := 10
+		err = UnmarshalRow(rows, &age, WithNullAsZero())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, age)
+
+		rs = mock.NewRows([]string{"age"}).AddRow(nil).AddRow(int64(20))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select age from user")
+		assert.Nil(t, err)
+
+		var ages []int64
+		err = UnmarshalRows(rows, &ages, WithNullAsZero())
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{0, 20}, ages)
+	})
+}
@@ -0,0 +1,110 @@
+package sqlx
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	defaultOptions     options
+	defaultOptionsLock sync.RWMutex
+)
+
+// Option customizes the behavior of UnmarshalRow and UnmarshalRows, and the
+// functions mapping struct fields into columns such as MarshalRow and Columns.
+type Option func(o *options)
+
+type options struct {
+	strict           bool
+	requireAllFields bool
+	nameMapper       NameMapper
+	caseInsensitive  bool
+	nullAsZero       bool
+	dialect          Dialect
+}
+
+// SetDefaultOptions sets the options applied to every call, the options passed
+// to a call will be applied after them.
+func SetDefaultOptions(opts ...Option) {
+	defaultOptionsLock.Lock()
+	defer defaultOptionsLock.Unlock()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	defaultOptions = o
+}
+
+// WithStrict returns an Option which reports an error listing the columns
+// that can not be mapped to any struct field and the struct fields that
+// receive no column, instead of discarding the unknown columns silently.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithRequireAllFields returns an Option which reports an error if the result set
+// has fewer columns than the struct fields, by default a subset of columns is
+// allowed and only the matching fields will be populated.
+func WithRequireAllFields() Option {
+	return func(o *options) {
+		o.requireAllFields = true
+	}
+}
+
+// WithNameMapper returns an Option which maps the names of the struct fields
+// without db tag into column names by mapper, such as SnakeCaseMapper.
+func WithNameMapper(mapper NameMapper) Option {
+	return func(o *options) {
+		o.nameMapper = mapper
+	}
+}
+
+// WithCaseInsensitive returns an Option which matches the columns and the
+// struct fields case-insensitively.
+func WithCaseInsensitive() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithNullAsZero returns an Option which scans NULL into the non-pointer fields
+// and the basic-type values as zero values, or as the default values specified
+// by tag like `db:"age,default=18"`, instead of failing in rows.Scan.
+func WithNullAsZero() Option {
+	return func(o *options) {
+		o.nullAsZero = true
+	}
+}
+
+// WithDialect returns an Option which parses the queries by the quoting rules
+// of d, such as the backslash escapes of MySQL, the standard SQL quoting rules
+// are used by default.
+func WithDialect(d Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+// columnKey returns the key to match a column with struct fields
+func (o *options) columnKey(column string) string {
+	if o.caseInsensitive {
+		return strings.ToLower(column)
+	}
+
+	return column
+}
+
+func newOptions(opts []Option) *options {
+	defaultOptionsLock.RLock()
+	o := defaultOptions
+	defaultOptionsLock.RUnlock()
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &o
+}
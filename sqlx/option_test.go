@@ -0,0 +1,119 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStrict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Bar struct {
+		IdNumber string `db:"id_number"`
+	}
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+		Bar
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "nmae", "id_number"}).FromCSVString("1,test,1001")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,nmae,id_number from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo, WithStrict())
+		assert.EqualError(t, err, "strict mode: unknown columns [nmae], unfilled fields [Name]")
+	})
+
+	t.Run("slice mismatch", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "number"}).FromCSVString("1,test,1001")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name,number from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithStrict())
+		assert.EqualError(t, err, "strict mode: unknown columns [number], unfilled fields [Bar.IdNumber]")
+	})
+
+	t.Run("match", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "id_number"}).FromCSVString("1,test,1001")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name,id_number from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo, WithStrict())
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Name: "test", Bar: Bar{IdNumber: "1001"}}}, foo)
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		SetDefaultOptions(WithStrict())
+		defer SetDefaultOptions()
+
+		rs := mock.NewRows([]string{"id", "name", "id_number", "age"}).FromCSVString("1,test,1001,20")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name,id_number,age from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.EqualError(t, err, "strict mode: unknown columns [age], unfilled fields []")
+	})
+
+	t.Run("session", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "id_number", "age"}).FromCSVString("1,test,1001,20")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+
+		var foo Foo
+		err := NewSession(db, WithStrict()).QueryRow(&foo, "select id,name,id_number,age from user")
+		assert.NotNil(t, err)
+
+		rs = mock.NewRows([]string{"id", "name", "id_number", "age"}).FromCSVString("1,test,1001,20")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		err = NewSession(db).QueryRow(&foo, "select id,name,id_number,age from user")
+		assert.Nil(t, err)
+	})
+}
+
+func TestWithRequireAllFields(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+		Age  int    `db:"age"`
+	}
+
+	t.Run("partial", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Name: "test1"}, {Id: 2, Name: "test2"}}, foo)
+	})
+
+	t.Run("required", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo, WithRequireAllFields())
+		assert.EqualError(t, err, "expected column num 3, but found 2")
+	})
+}
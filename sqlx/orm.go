@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var (
-	errInvalidPointer = errors.New("invalid pointer")
-	errNotSettable    = errors.New("can not settable")
+	errInvalidPointer  = errors.New("invalid pointer")
+	errNotSettable     = errors.New("can not settable")
+	errUnsupportedType = errors.New("unsupported type")
+
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
 
 	// ErrNoRows is an alias of sql.ErrNoRows
 	ErrNoRows = sql.ErrNoRows
@@ -18,7 +23,7 @@ var (
 
 // UnmarshalRow accepts an interface to scan in, there is one row could be scan even though
 // there are more than one rows, an ErrNoRows will be returned if have no rows.
-func UnmarshalRow(rows *sql.Rows, v interface{}) error {
+func UnmarshalRow(rows *sql.Rows, v interface{}, opts ...Option) error {
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
 			return err
@@ -32,22 +37,27 @@ func UnmarshalRow(rows *sql.Rows, v interface{}) error {
 
 	t := reflect.TypeOf(v)
 	it := indirect(t)
-	switch it.Kind() {
-	case reflect.Bool, reflect.Int, reflect.Int8,
-		reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16,
-		reflect.Uint32, reflect.Uint64, reflect.Float32,
-		reflect.Float64, reflect.String:
-		return scanBasicRow(rows, v)
-	case reflect.Struct:
-		return scanStructRow(rows, v)
+	var err error
+	switch {
+	case isScalar(it):
+		err = scanBasicRow(rows, v, newOptions(opts))
+	case it.Kind() == reflect.Struct:
+		err = scanStructRow(rows, v, newOptions(opts))
+	case isMap(it):
+		err = scanMapRow(rows, v)
 	default:
-		return errors.New("unsupported type")
+		err = errUnsupportedType
+	}
+	if err != nil {
+		return err
 	}
+
+	return afterScan(v)
 }
 
-// UnmarshalRows accepts an interface which type must be ptr-slice
-func UnmarshalRows(rows *sql.Rows, v interface{}) error {
+// UnmarshalRows accepts an interface which type must be ptr-slice, the error of
+// rows will be returned after the last row.
+func UnmarshalRows(rows *sql.Rows, v interface{}, opts ...Option) error {
 	if err := must(v); err != nil {
 		return err
 	}
@@ -64,156 +74,237 @@ func UnmarshalRows(rows *sql.Rows, v interface{}) error {
 
 	item := slice.Elem()
 	itemBaseType := indirect(item)
-	switch itemBaseType.Kind() {
-	case reflect.Bool, reflect.Int, reflect.Int8,
-		reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16,
-		reflect.Uint32, reflect.Uint64, reflect.Float32,
-		reflect.Float64, reflect.String:
-		for rows.Next() {
-			value := reflect.New(itemBaseType)
-			err := rows.Scan(value.Interface())
-			if err != nil {
-				return err
-			}
-
-			if item.Kind() == reflect.Ptr {
-				slicev.Set(reflect.Append(slicev, value))
-			} else {
-				slicev.Set(reflect.Append(slicev, reflect.Indirect(value)))
-			}
-		}
-	case reflect.Struct:
-		columns, err := rows.Columns()
+	scan, err := newValueScanner(rows, itemBaseType, newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		value, err := scan()
 		if err != nil {
 			return err
 		}
 
-		for rows.Next() {
-			value := reflect.New(itemBaseType)
-			list, err := convertStructFieldsIntoInterfaceSlice(value, columns)
-			if err != nil {
-				return err
-			}
-
-			err = rows.Scan(list...)
-			if err != nil {
-				return err
-			}
-
-			if item.Kind() == reflect.Ptr {
-				slicev.Set(reflect.Append(slicev, value))
-			} else {
-				slicev.Set(reflect.Append(slicev, reflect.Indirect(value)))
-			}
+		appendValue(slicev, item, value)
+	}
+
+	return rows.Err()
+}
+
+// appendValue appends the value, which is a pointer of the base type of item,
+// to the slice.
+func appendValue(slice reflect.Value, item reflect.Type, value reflect.Value) {
+	if item.Kind() == reflect.Ptr {
+		slice.Set(reflect.Append(slice, value))
+	} else {
+		slice.Set(reflect.Append(slice, reflect.Indirect(value)))
+	}
+}
+
+// rowScanner scans each row of a result set into a value of a scalar type or
+// a struct type.
+type rowScanner struct {
+	plan   *scanPlan
+	scalar destFunc
+}
+
+func newRowScanner(t reflect.Type, columns []string, o *options) (*rowScanner, error) {
+	switch {
+	case isScalar(t):
+		return &rowScanner{scalar: newDestFunc(t, Tag{}, o)}, nil
+	case t.Kind() == reflect.Struct:
+		plan, err := newScanPlan(t, columns, o)
+		if err != nil {
+			return nil, err
 		}
+
+		return &rowScanner{plan: plan}, nil
 	default:
-		return errors.New("unsupported type")
+		return nil, errUnsupportedType
 	}
+}
 
-	return nil
+// dest returns the scan destinations of an addressable value
+func (s *rowScanner) dest(v reflect.Value) []interface{} {
+	if s.plan == nil {
+		return []interface{}{s.scalar(v)}
+	}
+
+	return s.plan.dest(v)
 }
 
-func scanStructRow(rows *sql.Rows, v interface{}) error {
+func scanStructRow(rows *sql.Rows, v interface{}, o *options) error {
 	columns, err := rows.Columns()
 	if err != nil {
 		return err
 	}
 
-	value := reflect.ValueOf(v)
-	list, err := convertStructFieldsIntoInterfaceSlice(value, columns)
+	value := reflect.Indirect(reflect.ValueOf(v))
+	plan, err := newScanPlan(value.Type(), columns, o)
 	if err != nil {
 		return err
 	}
 
-	return rows.Scan(list...)
+	return scanRow(rows, plan.dest(value), 0, columns, value.Type(), plan)
 }
 
-func convertStructFieldsIntoInterfaceSlice(v reflect.Value, columns []string) ([]interface{}, error) {
-	fields, err := getFields(v)
-	if err != nil {
-		return nil, err
+// scanPlan maps the columns of a result set to the fields of a struct type,
+// it is computed once per result set and reused by every row.
+type scanPlan struct {
+	// fields holds the field of each column, nil if the column is not mapped.
+	fields []*fieldInfo
+	// dests holds the destFunc of each mapped column
+	dests   []destFunc
+	discard interface{}
+}
+
+// destFunc returns the scan destination of an addressable value
+type destFunc func(v reflect.Value) interface{}
+
+func addrDest(v reflect.Value) interface{} {
+	return v.Addr().Interface()
+}
+
+// newDestFunc returns the destFunc of the values of type t, which are scanned
+// directly by default, or through a wrapper sql.Scanner customized by the tag
+// and the options.
+func newDestFunc(t reflect.Type, tag Tag, o *options) destFunc {
+	if tag.JSON {
+		return func(v reflect.Value) interface{} {
+			return &jsonScanner{value: v}
+		}
 	}
 
-	if len(columns) < len(fields) {
-		return nil, fmt.Errorf("expected column num %d, but found %d", len(columns), len(fields))
+	if convert, ok := getConverter(indirect(t)); ok {
+		return func(v reflect.Value) interface{} {
+			return &converterScanner{value: v, convert: convert}
+		}
 	}
 
-	list := make([]interface{}, 0)
-	for _, column := range columns {
-		if v, ok := fields[column]; ok {
-			list = append(list, v.Interface())
-		} else {
-			var anonymous interface{}
-			list = append(list, &anonymous)
+	if o.nullAsZero && isNullable(t) {
+		def := tag.Default
+		return func(v reflect.Value) interface{} {
+			return &nullScanner{value: v, def: def}
 		}
 	}
 
-	return list, nil
+	return addrDest
 }
 
-func getFields(v reflect.Value) (map[string]reflect.Value, error) {
-	ve := reflect.Indirect(v)
-	vt := indirect(ve.Type())
-	fields := make(map[string]reflect.Value)
-	for i := 0; i < ve.NumField(); i++ {
-		fv := ve.Field(i)
-		ft := vt.Field(i)
-		tag := getTag(ft)
+func newScanPlan(t reflect.Type, columns []string, o *options) (*scanPlan, error) {
+	info, err := getStructInfo(t)
+	if err != nil {
+		return nil, err
+	}
+
+	columnMap := info.columnMap(o)
+	if o.strict {
+		if err := checkStrict(info, columnMap, columns, o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.requireAllFields && len(columns) < len(columnMap) {
+		return nil, fmt.Errorf("expected column num %d, but found %d", len(columnMap), len(columns))
+	}
+
+	plan := &scanPlan{
+		fields: make([]*fieldInfo, len(columns)),
+		dests:  make([]destFunc, len(columns)),
+	}
+	for i, column := range columns {
+		field := columnMap[o.columnKey(column)]
+		plan.fields[i] = field
+		if field != nil {
+			plan.dests[i] = newDestFunc(t.FieldByIndex(field.index).Type, field.tag, o)
+		}
+	}
 
-		fvt := indirect(fv.Type())
-		if fv.Kind() == reflect.Ptr && fv.IsNil() {
-			if !fv.CanInterface() {
-				return nil, errNotSettable
-			}
+	return plan, nil
+}
 
-			nfv := reflect.New(indirect(fv.Type()))
-			fv.Set(nfv)
-			fvt = indirect(fvt)
+func checkStrict(info *structInfo, columnMap map[string]*fieldInfo, columns []string, o *options) error {
+	present := make(map[string]struct{}, len(columns))
+	var unknownColumns, unfilledFields []string
+	for _, column := range columns {
+		key := o.columnKey(column)
+		present[key] = struct{}{}
+		if _, ok := columnMap[key]; !ok {
+			unknownColumns = append(unknownColumns, column)
 		}
+	}
 
-		if fvt.Kind() == reflect.Struct && ft.Anonymous {
-			fds, err := getFields(fv)
-			if err != nil {
-				return nil, err
-			}
-
-			for k, v := range fds {
-				fields[k] = v
-			}
-		} else {
-			if !fv.CanAddr() || !fv.Addr().CanInterface() {
-				return nil, errNotSettable
-			}
-
-			fields[tag] = fv.Addr()
+	for _, field := range info.fields {
+		if _, ok := present[o.columnKey(field.columnName(o.nameMapper))]; !ok {
+			unfilledFields = append(unfilledFields, field.path)
 		}
 	}
 
-	return fields, nil
+	if len(unknownColumns) == 0 && len(unfilledFields) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("strict mode: unknown columns [%s], unfilled fields [%s]",
+		strings.Join(unknownColumns, ", "), strings.Join(unfilledFields, ", "))
 }
 
-func getTag(f reflect.StructField) string {
-	tag, ok := f.Tag.Lookup("db")
-	if !ok {
-		tag = f.Name
-	} else {
-		index := strings.Index(tag, ",")
-		if index > 0 {
-			tag = tag[:index]
+// dest returns the scan destinations of an addressable struct value, the nil
+// pointer structs are allocated only if any of their fields is mapped to a
+// column, otherwise they are left nil.
+func (p *scanPlan) dest(v reflect.Value) []interface{} {
+	list := make([]interface{}, len(p.fields))
+	for i, field := range p.fields {
+		if field == nil {
+			list[i] = &p.discard
+			continue
 		}
+
+		list[i] = p.dests[i](fieldByIndex(v, field.index))
 	}
 
-	return tag
+	return list
 }
-func scanBasicRow(rows *sql.Rows, v interface{}) error {
-	value := reflect.ValueOf(v)
-	elem := reflect.Indirect(value)
+
+func scanBasicRow(rows *sql.Rows, v interface{}, o *options) error {
+	elem := reflect.Indirect(reflect.ValueOf(v))
 	if !elem.CanSet() {
 		return errNotSettable
 	}
 
-	return rows.Scan(value.Interface())
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	return scanRow(rows, []interface{}{newDestFunc(elem.Type(), Tag{}, o)(elem)}, 0, columns, elem.Type(), nil)
+}
+
+// isScalar reports whether t should be scanned from a single column, they are
+// the basic types, the implementations of sql.Scanner, time.Time and the byte
+// slices such as []byte and json.RawMessage.
+func isScalar(t reflect.Type) bool {
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return true
+	}
+
+	if _, ok := getConverter(t); ok {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8,
+		reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Float32,
+		reflect.Float64, reflect.String:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Struct:
+		return t == timeType
+	default:
+		return false
+	}
 }
 
 func indirect(t reflect.Type) reflect.Type {
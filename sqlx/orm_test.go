@@ -1,7 +1,12 @@
 package sqlx
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -137,6 +142,22 @@ func TestOrm(t *testing.T) {
 		}, foo)
 	})
 
+	t.Run("slice rows error", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2").RowError(1, errors.New("boom"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+
+		type Foo struct {
+			Id   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		var foo []Foo
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		err = UnmarshalRows(rows, &foo)
+		assert.EqualError(t, err, "boom")
+	})
+
 	t.Run("slice pointer struct", func(t *testing.T) {
 		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
 		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
@@ -162,4 +183,184 @@ func TestOrm(t *testing.T) {
 			},
 		}, foo)
 	})
+
+	t.Run("scanner", func(t *testing.T) {
+		now := time.Now()
+		rs := mock.NewRows([]string{"created_at"}).AddRow(now)
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
+		rows, err := db.Query("select created_at from user where id = ?", 1)
+		assert.Nil(t, err)
+
+		var createdAt time.Time
+		err = UnmarshalRow(rows, &createdAt)
+		assert.Nil(t, err)
+		assert.Equal(t, now, createdAt)
+
+		rs = mock.NewRows([]string{"name"}).AddRow(nil)
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
+		rows, err = db.Query("select name from user where id = ?", 1)
+		assert.Nil(t, err)
+
+		var name sql.NullString
+		err = UnmarshalRow(rows, &name)
+		assert.Nil(t, err)
+		assert.False(t, name.Valid)
+
+		rs = mock.NewRows([]string{"settings"}).AddRow([]byte(`{"a":1}`))
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
+		rows, err = db.Query("select settings from user where id = ?", 1)
+		assert.Nil(t, err)
+
+		var settings json.RawMessage
+		err = UnmarshalRow(rows, &settings)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"a":1}`, string(settings))
+	})
+
+	t.Run("slice scanner", func(t *testing.T) {
+		now := time.Now()
+		rs := mock.NewRows([]string{"created_at"}).AddRow(now).AddRow(now.Add(time.Second))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select created_at from user")
+		assert.Nil(t, err)
+
+		var createdAt []time.Time
+		err = UnmarshalRows(rows, &createdAt)
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{now, now.Add(time.Second)}, createdAt)
+
+		rs = mock.NewRows([]string{"age"}).AddRow(20).AddRow(nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select age from user")
+		assert.Nil(t, err)
+
+		var ages []*sql.NullInt64
+		err = UnmarshalRows(rows, &ages)
+		assert.Nil(t, err)
+		assert.Equal(t, []*sql.NullInt64{{Int64: 20, Valid: true}, {}}, ages)
+
+		rs = mock.NewRows([]string{"data"}).AddRow([]byte("a")).AddRow([]byte("b"))
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select data from user")
+		assert.Nil(t, err)
+
+		var data [][]byte
+		err = UnmarshalRows(rows, &data)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, data)
+	})
+
+	t.Run("struct scanner field", func(t *testing.T) {
+		now := time.Now()
+		rs := mock.NewRows([]string{"id", "Time", "nickname"}).AddRow(1, now, nil)
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs)
+		type Foo struct {
+			Id int64 `db:"id"`
+			time.Time
+			Nickname sql.NullString `db:"nickname"`
+		}
+
+		rows, err := db.Query("select id,Time,nickname from user where id = ?", 1)
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, Foo{Id: 1, Time: now}, foo)
+	})
+
+	t.Run("struct private state", func(t *testing.T) {
+		type Foo struct {
+			Id       int64  `db:"id"`
+			Name     string `db:"name"`
+			mu       sync.Mutex
+			loaded   bool
+			Callback func() error
+		}
+
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id,name from user")
+		assert.Nil(t, err)
+
+		var foo []*Foo
+		err = UnmarshalRows(rows, &foo, WithStrict())
+		assert.Nil(t, err)
+		assert.Equal(t, []*Foo{{Id: 1, Name: "test"}}, foo)
+
+		columns, values, err := MarshalRow(foo[0])
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "name"}, columns)
+		assert.Equal(t, []interface{}{int64(1), "test"}, values)
+	})
+
+	t.Run("struct lazy embedded", func(t *testing.T) {
+		type Audit struct {
+			CreatedBy string `db:"created_by"`
+		}
+
+		type Foo struct {
+			Id int64 `db:"id"`
+			*Audit
+		}
+
+		rs := mock.NewRows([]string{"id"}).FromCSVString("1")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select id from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1}}, foo)
+
+		rs = mock.NewRows([]string{"id", "created_by"}).FromCSVString("1,admin")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err = db.Query("select id,created_by from user")
+		assert.Nil(t, err)
+
+		foo = nil
+		err = UnmarshalRows(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Audit: &Audit{CreatedBy: "admin"}}}, foo)
+	})
+
+	t.Run("struct prefix", func(t *testing.T) {
+		type Geo struct {
+			Lat float64 `db:"lat"`
+			Lng float64 `db:"lng"`
+		}
+
+		type Address struct {
+			City string `db:"city"`
+			Geo  *Geo   `db:"geo,prefix=_"`
+		}
+
+		type Foo struct {
+			Id      int64   `db:"id"`
+			Address Address `db:"addr,prefix"`
+			Company Address `db:"company,prefix=_"`
+		}
+
+		rs := mock.NewRows([]string{"id", "addr.city", "addr.geo_lat", "addr.geo_lng", "company_city"}).
+			FromCSVString("1,beijing,39.9,116.4,shanghai")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo []*Foo
+		err = UnmarshalRows(rows, &foo)
+		assert.Nil(t, err)
+		assert.Equal(t, []*Foo{{
+			Id: 1,
+			Address: Address{
+				City: "beijing",
+				Geo:  &Geo{Lat: 39.9, Lng: 116.4},
+			},
+			Company: Address{
+				City: "shanghai",
+			},
+		}}, foo)
+		assert.Equal(t, []string{"id", "addr.city", "addr.geo_lat", "addr.geo_lng", "company_city", "company_geo_lat", "company_geo_lng"}, mustColumns(t, Foo{}))
+	})
 }
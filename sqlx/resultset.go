@@ -0,0 +1,47 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// UnmarshalResultSets fills the destinations from the successive result sets of
+// rows, such as the results of a stored procedure, one destination per result
+// set. A pointer of slice, except []byte and the like, is filled by UnmarshalRows,
+// the others are filled by UnmarshalRow. An error will be returned if the number
+// of result sets doesn't match the number of destinations.
+func UnmarshalResultSets(rows *sql.Rows, dests ...interface{}) error {
+	for i, dest := range dests {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("expected result set num %d, but found %d", len(dests), i)
+		}
+
+		if err := unmarshalResultSet(rows, dest); err != nil {
+			return fmt.Errorf("result set %d: %w", i, err)
+		}
+	}
+
+	if rows.NextResultSet() {
+		return fmt.Errorf("expected result set num %d, but found more", len(dests))
+	}
+
+	return rows.Err()
+}
+
+func unmarshalResultSet(rows *sql.Rows, dest interface{}) error {
+	if err := must(dest); err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(dest).Elem()
+	if t.Kind() == reflect.Slice && !isScalar(t) {
+		return UnmarshalRows(rows, dest)
+	}
+
+	return UnmarshalRow(rows, dest)
+}
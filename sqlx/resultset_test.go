@@ -0,0 +1,80 @@
+package sqlx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalResultSets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	newRows := func() []*sqlmock.Rows {
+		return []*sqlmock.Rows{
+			mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2"),
+			mock.NewRows([]string{"count"}).FromCSVString("2"),
+		}
+	}
+
+	t.Run("match", func(t *testing.T) {
+		mock.ExpectQuery("call list_user").WillReturnRows(newRows()...)
+		rows, err := db.Query("call list_user()")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var foo []Foo
+		var count int
+		err = UnmarshalResultSets(rows, &foo, &count)
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Name: "test1"}, {Id: 2, Name: "test2"}}, foo)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("fewer result sets", func(t *testing.T) {
+		mock.ExpectQuery("call list_user").WillReturnRows(newRows()...)
+		rows, err := db.Query("call list_user()")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var foo []Foo
+		var count int
+		var names []string
+		err = UnmarshalResultSets(rows, &foo, &count, &names)
+		assert.EqualError(t, err, "expected result set num 3, but found 2")
+	})
+
+	t.Run("more result sets", func(t *testing.T) {
+		mock.ExpectQuery("call list_user").WillReturnRows(newRows()...)
+		rows, err := db.Query("call list_user()")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var foo []Foo
+		err = UnmarshalResultSets(rows, &foo)
+		assert.EqualError(t, err, "expected result set num 1, but found more")
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		mock.ExpectQuery("call list_user").WillReturnRows(
+			mock.NewRows([]string{"id", "name"}),
+			mock.NewRows([]string{"count"}),
+		)
+		rows, err := db.Query("call list_user()")
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var foo []Foo
+		var count int
+		err = UnmarshalResultSets(rows, &foo, &count)
+		assert.True(t, errors.Is(err, ErrNoRows))
+		assert.Empty(t, foo)
+	})
+}
@@ -0,0 +1,111 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanError describes an error of scanning a row, which carries the index of
+// the row, the column, the path of the struct field and the Go type the column
+// is scanned into, the context that can not be found is left empty.
+// The error returned by rows.Scan can be unwrapped by errors.Is and errors.As.
+// The row is scanned again column by column to locate the failed one, so the
+// sql.Scanner implementations and the Converters may be called more than once,
+// and the values scanned before the error should not be relied on.
+type ScanError struct {
+	// Row is the index of the row, starting from 0
+	Row int
+	// Column is the name of the column failed
+	Column string
+	// Field is the path of the struct field, such as Bar.IdNumber
+	Field string
+	// Type is the type of the struct field or the scanned value
+	Type reflect.Type
+	// Err is the error returned by rows.Scan
+	Err error
+}
+
+// Error implements error
+func (e *ScanError) Error() string {
+	list := []string{fmt.Sprintf("row %d", e.Row)}
+	if e.Column != "" {
+		list = append(list, fmt.Sprintf("column %q", e.Column))
+	}
+
+	if e.Field != "" {
+		list = append(list, "field "+e.Field)
+	}
+
+	if e.Type != nil {
+		list = append(list, "type "+e.Type.String())
+	}
+
+	return "scan error on " + strings.Join(list, ", ") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by rows.Scan
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// scanRow scans the current row into dest, the error of rows.Scan is wrapped
+// into a ScanError, the struct field is looked up from plan if the row is
+// scanned into a value of struct type t. The destinations may be scanned more
+// than once on failure, see failedColumn.
+func scanRow(rows *sql.Rows, dest []interface{}, row int, columns []string, t reflect.Type, plan *scanPlan) error {
+	err := rows.Scan(dest...)
+	if err == nil {
+		return nil
+	}
+
+	return newScanError(err, row, failedColumn(rows, dest, columns), columns, t, plan)
+}
+
+// failedColumn returns the index of the first destination which fails to be
+// scanned, by scanning the current row again with the other destinations
+// discarded, -1 is returned if no destination fails alone.
+func failedColumn(rows *sql.Rows, dest []interface{}, columns []string) int {
+	if len(dest) != len(columns) {
+		return -1
+	}
+
+	var discard interface{}
+	list := make([]interface{}, len(dest))
+	for i := range dest {
+		for j := range list {
+			list[j] = &discard
+		}
+		list[i] = dest[i]
+
+		if rows.Scan(list...) != nil {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func newScanError(err error, row, index int, columns []string, t reflect.Type, plan *scanPlan) error {
+	scanErr := &ScanError{
+		Row:  row,
+		Type: t,
+		Err:  err,
+	}
+
+	if index < 0 || index >= len(columns) {
+		return scanErr
+	}
+
+	scanErr.Column = columns[index]
+	if plan != nil {
+		scanErr.Type = nil
+		if field := plan.fields[index]; field != nil {
+			scanErr.Field = field.path
+			scanErr.Type = t.FieldByIndex(field.index).Type
+		}
+	}
+
+	return scanErr
+}
@@ -0,0 +1,173 @@
+package sqlx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Bar struct {
+		Age int64 `db:"age"`
+	}
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+		Bar
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "age"}).AddRow(1, "foo", 20).AddRow(2, "bar", nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo []Foo
+		err = UnmarshalRows(rows, &foo)
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, 1, scanErr.Row)
+		assert.Equal(t, "age", scanErr.Column)
+		assert.Equal(t, "Bar.Age", scanErr.Field)
+		assert.Equal(t, reflect.TypeOf(int64(0)), scanErr.Type)
+		assert.NotNil(t, errors.Unwrap(err))
+		assert.Contains(t, err.Error(), `scan error on row 1, column "age", field Bar.Age, type int64: `)
+	})
+
+	t.Run("row", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "age"}).AddRow("x", "foo", 20)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var foo Foo
+		err = UnmarshalRow(rows, &foo)
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, 0, scanErr.Row)
+		assert.Equal(t, "id", scanErr.Column)
+		assert.Equal(t, "Id", scanErr.Field)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		rs := mock.NewRows([]string{"age"}).AddRow(nil)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select age from user")
+		assert.Nil(t, err)
+
+		var age int64
+		err = UnmarshalRow(rows, &age)
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, "age", scanErr.Column)
+		assert.Equal(t, "", scanErr.Field)
+		assert.Equal(t, reflect.TypeOf(age), scanErr.Type)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		rs := mock.NewRows([]string{"age"})
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select age from user")
+		assert.Nil(t, err)
+
+		var age int64
+		err = UnmarshalRow(rows, &age)
+		assert.True(t, errors.Is(err, ErrNoRows))
+	})
+
+	t.Run("scanner", func(t *testing.T) {
+		type Baz struct {
+			Id    int64        `db:"id"`
+			Value failScanner  `db:"value"`
+			Extra *failScanner `db:"extra"`
+		}
+
+		rs := mock.NewRows([]string{"id", "value", "extra"}).AddRow(1, "ok", "ok").AddRow(2, "ok", "fail")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var count int
+		err = UnmarshalEach(rows, func(v *Baz) error {
+			count++
+			return nil
+		})
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 1, scanErr.Row)
+		assert.Equal(t, "extra", scanErr.Column)
+		assert.Equal(t, "Extra", scanErr.Field)
+		assert.Equal(t, reflect.TypeOf(&failScanner{}), scanErr.Type)
+	})
+
+	t.Run("rescan", func(t *testing.T) {
+		type Baz struct {
+			Count countScanner `db:"count"`
+			Value failScanner  `db:"value"`
+		}
+
+		rs := mock.NewRows([]string{"count", "value"}).AddRow("ok", "fail")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var baz Baz
+		err = UnmarshalRow(rows, &baz)
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, "value", scanErr.Column)
+		// the columns before the failed one are scanned again to locate it
+		assert.Equal(t, countScanner(2), baz.Count)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name", "age"}).AddRow(1, "foo", "x")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+		rows, err := db.Query("select * from user")
+		assert.Nil(t, err)
+
+		var m map[int64]Foo
+		err = UnmarshalRowsToMap(rows, &m, "id")
+		var scanErr *ScanError
+		assert.True(t, errors.As(err, &scanErr))
+		assert.Equal(t, "age", scanErr.Column)
+		assert.Equal(t, "Bar.Age", scanErr.Field)
+	})
+
+	t.Run("without column", func(t *testing.T) {
+		err := newScanError(errors.New("any"), 2, -1, nil, reflect.TypeOf(Foo{}), nil)
+		assert.EqualError(t, err, "scan error on row 2, type sqlx.Foo: any")
+	})
+}
+
+var errScanFailed = errors.New("scan failed")
+
+// countScanner counts the times it's scanned
+type countScanner int
+
+func (s *countScanner) Scan(interface{}) error {
+	*s++
+	return nil
+}
+
+type failScanner struct{}
+
+func (s *failScanner) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok && string(b) == "fail" {
+		return errScanFailed
+	}
+
+	if v, ok := src.(string); ok && v == "fail" {
+		return errScanFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Conn is the common interface of *sql.DB, *sql.Tx and *sql.Conn
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Session executes sql statements and unmarshals the results, the rows opened
+// by Session will be closed before returning.
+type Session interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecCtx(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRow(v interface{}, query string, args ...interface{}) error
+	QueryRowCtx(ctx context.Context, v interface{}, query string, args ...interface{}) error
+	QueryRows(v interface{}, query string, args ...interface{}) error
+	QueryRowsCtx(ctx context.Context, v interface{}, query string, args ...interface{}) error
+}
+
+type defaultSession struct {
+	conn Conn
+	opts []Option
+}
+
+// NewSession returns a Session which wraps a *sql.DB, *sql.Tx or *sql.Conn,
+// the options will be applied to every query of the Session.
+func NewSession(conn Conn, opts ...Option) Session {
+	return &defaultSession{
+		conn: conn,
+		opts: opts,
+	}
+}
+
+func (s *defaultSession) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecCtx(context.Background(), query, args...)
+}
+
+func (s *defaultSession) ExecCtx(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.conn.ExecContext(ctx, query, args...)
+}
+
+func (s *defaultSession) QueryRow(v interface{}, query string, args ...interface{}) error {
+	return s.QueryRowCtx(context.Background(), v, query, args...)
+}
+
+func (s *defaultSession) QueryRowCtx(ctx context.Context, v interface{}, query string, args ...interface{}) error {
+	return s.query(ctx, func(rows *sql.Rows) error {
+		return UnmarshalRow(rows, v, s.opts...)
+	}, query, args...)
+}
+
+func (s *defaultSession) QueryRows(v interface{}, query string, args ...interface{}) error {
+	return s.QueryRowsCtx(context.Background(), v, query, args...)
+}
+
+func (s *defaultSession) QueryRowsCtx(ctx context.Context, v interface{}, query string, args ...interface{}) error {
+	return s.query(ctx, func(rows *sql.Rows) error {
+		return UnmarshalRows(rows, v, s.opts...)
+	}, query, args...)
+}
+
+func (s *defaultSession) query(ctx context.Context, scan func(rows *sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+	return scan(rows)
+}
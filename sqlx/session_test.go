@@ -0,0 +1,97 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	t.Run("QueryRowCtx", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test")
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs).RowsWillBeClosed()
+
+		var foo Foo
+		err := NewSession(db).QueryRowCtx(context.Background(), &foo, "select id,name from user where id = ?", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, Foo{Id: 1, Name: "test"}, foo)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("QueryRow no rows", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"})
+		mock.ExpectQuery("select (.+) from user where id = ?").WithArgs(1).WillReturnRows(rs).RowsWillBeClosed()
+
+		var foo Foo
+		err := NewSession(db).QueryRow(&foo, "select id,name from user where id = ?", 1)
+		assert.Equal(t, ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("QueryRowsCtx", func(t *testing.T) {
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2")
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs).RowsWillBeClosed()
+
+		var foo []Foo
+		err := NewSession(db).QueryRowsCtx(context.Background(), &foo, "select id,name from user")
+		assert.Nil(t, err)
+		assert.Equal(t, []Foo{{Id: 1, Name: "test1"}, {Id: 2, Name: "test2"}}, foo)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("QueryRows row error", func(t *testing.T) {
+		rowErr := errors.New("row error")
+		rs := mock.NewRows([]string{"id", "name"}).FromCSVString("1,test1\n2,test2").RowError(1, rowErr)
+		mock.ExpectQuery("select (.+) from user").WillReturnRows(rs).RowsWillBeClosed()
+
+		var foo []Foo
+		err := NewSession(db).QueryRows(&foo, "select id,name from user")
+		assert.Equal(t, rowErr, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		queryErr := errors.New("query error")
+		mock.ExpectQuery("select (.+) from user").WillReturnError(queryErr)
+
+		var foo []Foo
+		err := NewSession(db).QueryRows(&foo, "select id,name from user")
+		assert.Equal(t, queryErr, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ExecCtx", func(t *testing.T) {
+		mock.ExpectExec("update user set name = ?").WithArgs("test").WillReturnResult(sqlmock.NewResult(0, 2))
+
+		result, err := NewSession(db).ExecCtx(context.Background(), "update user set name = ?", "test")
+		assert.Nil(t, err)
+		affected, err := result.RowsAffected()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), affected)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from user").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+		_, err = NewSession(tx).Exec("delete from user")
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,215 @@
+package sqlx
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	tagKey       = "db"
+	tagSkip      = "-"
+	tagOmitEmpty = "omitempty"
+	tagPrefix    = "prefix"
+
+	tagReadOnly      = "readonly"
+	tagPrimaryKey    = "pk"
+	tagAutoIncrement = "auto"
+	tagJSON          = "json"
+	tagDefault       = "default"
+
+	defaultPrefixSeparator = "."
+)
+
+// tagOptions is the string following a comma in a db tag, such as "omitempty"
+type tagOptions string
+
+// Contains reports whether a comma-separated list of options contains
+// the given option.
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var name string
+		i := strings.Index(s, ",")
+		if i >= 0 {
+			name, s = s[:i], s[i+1:]
+		} else {
+			name, s = s, ""
+		}
+
+		if strings.TrimSpace(name) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get returns the value of an option in the form of name=value, it also reports
+// true with an empty value if the option is present without a value.
+func (o tagOptions) Get(option string) (string, bool) {
+	s := string(o)
+	for s != "" {
+		var name string
+		i := strings.Index(s, ",")
+		if i >= 0 {
+			name, s = s[:i], s[i+1:]
+		} else {
+			name, s = s, ""
+		}
+
+		name = strings.TrimSpace(name)
+		if name == option {
+			return "", true
+		}
+
+		if strings.HasPrefix(name, option+"=") {
+			return name[len(option)+1:], true
+		}
+	}
+
+	return "", false
+}
+
+// Tag describes the db tag of a struct field, such as `db:"id,pk,auto"`, the
+// options are separated by commas after the column name.
+type Tag struct {
+	// Name is the column name, it's empty if the field has no db tag or the
+	// tag has no name, such as `db:",omitempty"`, in that case the field name
+	// mapped by NameMapper is used.
+	Name string
+	// Skip is set by `db:"-"`, the field is neither scanned nor marshaled
+	Skip bool
+	// OmitEmpty is set by omitempty, the field is not marshaled if it has
+	// a zero value.
+	OmitEmpty bool
+	// ReadOnly is set by readonly, the field is scanned but never marshaled,
+	// such as the columns maintained by database.
+	ReadOnly bool
+	// PrimaryKey is set by pk, the field is not assigned by UpdateBuilder.Struct
+	PrimaryKey bool
+	// AutoIncrement is set by auto, the field is not marshaled if it has a
+	// zero value, so that the database can generate it.
+	AutoIncrement bool
+	// JSON is set by json, the field is encoded as a json column
+	JSON bool
+	// Default is set by default=value, it's scanned into the field instead of
+	// NULL with the Option WithNullAsZero. The default of a time.Time field is
+	// parsed in the layout of time.RFC3339, 2006-01-02 15:04:05 or 2006-01-02.
+	Default string
+	// Prefix is the separator set by prefix or prefix=_, the fields of the
+	// nested struct are mapped to the columns starting with Name and Prefix.
+	Prefix string
+}
+
+// ParseTag parses the db tag of a struct field, a zero Tag is returned if the
+// field has no db tag.
+func ParseTag(f reflect.StructField) Tag {
+	tag, ok := f.Tag.Lookup(tagKey)
+	if !ok {
+		return Tag{}
+	}
+
+	if tag == tagSkip {
+		return Tag{Skip: true}
+	}
+
+	name, opts := tag, tagOptions("")
+	if i := strings.Index(tag, ","); i >= 0 {
+		name, opts = tag[:i], tagOptions(tag[i+1:])
+	}
+
+	def, _ := opts.Get(tagDefault)
+	prefix, ok := opts.Get(tagPrefix)
+	if ok && prefix == "" {
+		prefix = defaultPrefixSeparator
+	}
+
+	return Tag{
+		Name:          strings.TrimSpace(name),
+		OmitEmpty:     opts.Contains(tagOmitEmpty),
+		ReadOnly:      opts.Contains(tagReadOnly),
+		PrimaryKey:    opts.Contains(tagPrimaryKey),
+		AutoIncrement: opts.Contains(tagAutoIncrement),
+		JSON:          opts.Contains(tagJSON),
+		Default:       def,
+		Prefix:        prefix,
+	}
+}
+
+// omitted reports whether the field value v is not marshaled by the tag
+func (t Tag) omitted(v reflect.Value) bool {
+	if t.Skip || t.ReadOnly {
+		return true
+	}
+
+	return (t.OmitEmpty || t.AutoIncrement) && v.IsZero()
+}
+
+func isPrimaryKey(tag Tag) bool {
+	return tag.PrimaryKey
+}
+
+// ignoreField reports whether a struct field is ignored by the mapping, which
+// is tagged with "-", or it is an unexported field or a field of non-scannable
+// kind like func and interface without db tag, the unexported field with db tag
+// can not be ignored silently, errNotSettable will be returned.
+// The fields of an unexported embedded struct are still mapped since they
+// are promoted and settable.
+func ignoreField(f reflect.StructField, tag Tag) (bool, error) {
+	if tag.Skip {
+		return true, nil
+	}
+
+	_, tagged := f.Tag.Lookup(tagKey)
+	t := indirect(f.Type)
+	if f.Anonymous && f.Type.Kind() == reflect.Struct && !isScalar(t) {
+		return false, nil
+	}
+
+	if f.PkgPath != "" {
+		if tagged {
+			return false, errNotSettable
+		}
+
+		return true, nil
+	}
+
+	return !tagged && !isScannable(f.Type), nil
+}
+
+// isScannable reports whether t is possible to be scanned by rows.Scan
+func isScannable(t reflect.Type) bool {
+	if isScalar(indirect(t)) {
+		return true
+	}
+
+	switch indirect(t).Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.UnsafePointer:
+		return false
+	default:
+		return true
+	}
+}
+
+// isEmbedded reports whether f is an anonymous struct field whose fields are
+// expanded, the struct field tagged with json is encoded as a whole.
+func isEmbedded(f reflect.StructField, tag Tag) bool {
+	t := indirect(f.Type)
+	return f.Anonymous && t.Kind() == reflect.Struct && !isScalar(t) && !tag.JSON
+}
+
+// getPrefix returns the separator of a non-anonymous struct field which is
+// tagged with prefix, such as `db:"addr,prefix"` and `db:"addr,prefix=_"`,
+// the nested fields will be mapped to the columns like addr.city and addr_city.
+func getPrefix(f reflect.StructField, tag Tag) (string, bool) {
+	if f.Anonymous {
+		return "", false
+	}
+
+	t := indirect(f.Type)
+	if t.Kind() != reflect.Struct || isScalar(t) {
+		return "", false
+	}
+
+	return tag.Prefix, tag.Prefix != "" && !tag.JSON
+}
@@ -0,0 +1,103 @@
+package sqlx
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	type Address struct {
+		City string `db:"city"`
+	}
+
+	type Foo struct {
+		Id        int64 `db:"id,pk,auto"`
+		Name      string
+		Nickname  string  `db:",omitempty"`
+		Ignored   string  `db:"-"`
+		UpdatedAt string  `db:"updated_at,readonly"`
+		Extra     string  `db:"extra, json"`
+		Age       int     `db:"age,default=18"`
+		Address   Address `db:"addr,prefix=_"`
+		Company   Address `db:"company,prefix"`
+	}
+
+	ft := reflect.TypeOf(Foo{})
+	cases := []struct {
+		field    string
+		expected Tag
+	}{
+		{"Id", Tag{Name: "id", PrimaryKey: true, AutoIncrement: true}},
+		{"Name", Tag{}},
+		{"Nickname", Tag{OmitEmpty: true}},
+		{"Ignored", Tag{Skip: true}},
+		{"UpdatedAt", Tag{Name: "updated_at", ReadOnly: true}},
+		{"Extra", Tag{Name: "extra", JSON: true}},
+		{"Age", Tag{Name: "age", Default: "18"}},
+		{"Address", Tag{Name: "addr", Prefix: "_"}},
+		{"Company", Tag{Name: "company", Prefix: "."}},
+	}
+	for _, c := range cases {
+		f, _ := ft.FieldByName(c.field)
+		assert.Equal(t, c.expected, ParseTag(f), c.field)
+	}
+}
+
+func TestTagSkip(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	type Foo struct {
+		Id      int64  `db:"id"`
+		Ignored string `db:"-"`
+		secret  string `db:"-"`
+	}
+
+	rs := mock.NewRows([]string{"id", "-"}).FromCSVString("1,ignored")
+	mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+	rows, err := db.Query("select * from user")
+	assert.Nil(t, err)
+
+	var foo Foo
+	err = UnmarshalRow(rows, &foo, WithStrict())
+	assert.EqualError(t, err, "strict mode: unknown columns [-], unfilled fields []")
+
+	rs = mock.NewRows([]string{"id"}).FromCSVString("1")
+	mock.ExpectQuery("select (.+) from user").WillReturnRows(rs)
+	rows, err = db.Query("select * from user")
+	assert.Nil(t, err)
+
+	err = UnmarshalRow(rows, &foo, WithStrict())
+	assert.Nil(t, err)
+	assert.Equal(t, Foo{Id: 1}, foo)
+	assert.Equal(t, []string{"id"}, mustColumns(t, foo))
+}
+
+func TestFields(t *testing.T) {
+	type Address struct {
+		City string `db:"city"`
+	}
+
+	type Foo struct {
+		Id        int64 `db:"id,pk,auto"`
+		CreatedAt string
+		Ignored   string  `db:"-"`
+		Address   Address `db:"addr,prefix=_"`
+	}
+
+	fields, err := Fields(&Foo{}, WithNameMapper(SnakeCaseMapper))
+	assert.Nil(t, err)
+	assert.Equal(t, []Field{
+		{Column: "id", Path: "Id", Tag: Tag{Name: "id", PrimaryKey: true, AutoIncrement: true}},
+		{Column: "created_at", Path: "CreatedAt"},
+		{Column: "addr_city", Path: "Address.City", Tag: Tag{Name: "city"}},
+	}, fields)
+
+	_, err = Fields(nil)
+	assert.Equal(t, errNotStruct, err)
+	_, err = Fields(1)
+	assert.Equal(t, errNotStruct, err)
+}
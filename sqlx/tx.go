@@ -0,0 +1,86 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	errNotTransactable = errors.New("expected a *sql.DB, *sql.Conn or *sql.Tx")
+
+	savepointID uint64
+)
+
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Transact executes fn in a transaction, the transaction will be committed if
+// fn returns nil, and will be rolled back if fn returns an error or panics.
+// conn can be a *sql.DB or a *sql.Conn to start a new transaction, or a *sql.Tx
+// to start a nested transaction, which is implemented by SAVEPOINT, so that
+// the nested one could be rolled back without affecting the outer one, and
+// the caller doesn't need to know whether a transaction was already started.
+func Transact(ctx context.Context, conn Conn, fn func(tx *sql.Tx) error) error {
+	switch c := conn.(type) {
+	case *sql.Tx:
+		return transactSavepoint(ctx, c, fn)
+	case beginner:
+		tx, err := c.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		return transact(tx, fn)
+	default:
+		return errNotTransactable
+	}
+}
+
+func transact(tx *sql.Tx, fn func(tx *sql.Tx) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = fmt.Errorf("%w, rollback failed: %v", err, e)
+			}
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return fn(tx)
+}
+
+func transactSavepoint(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) (err error) {
+	savepoint := fmt.Sprintf("sp_%d", atomic.AddUint64(&savepointID, 1))
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(p)
+		}
+
+		if err != nil {
+			if _, e := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); e != nil {
+				err = fmt.Errorf("%w, rollback failed: %v", err, e)
+			}
+			return
+		}
+
+		_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	}()
+
+	return fn(tx)
+}
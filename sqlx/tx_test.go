@@ -0,0 +1,123 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransact(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	t.Run("commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into user").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := Transact(ctx, db, func(tx *sql.Tx) error {
+			_, err := tx.Exec("insert into user")
+			return err
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		fnErr := errors.New("fn error")
+		err := Transact(ctx, db, func(tx *sql.Tx) error {
+			return fnErr
+		})
+		assert.Equal(t, fnErr, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback().WillReturnError(errors.New("rollback error"))
+
+		fnErr := errors.New("fn error")
+		err := Transact(ctx, db, func(tx *sql.Tx) error {
+			return fnErr
+		})
+		assert.True(t, errors.Is(err, fnErr))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "test", func() {
+			_ = Transact(ctx, db, func(tx *sql.Tx) error {
+				panic("test")
+			})
+		})
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into orders").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		nestedErr := errors.New("nested error")
+		err := Transact(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec("insert into orders"); err != nil {
+				return err
+			}
+
+			err := Transact(ctx, tx, func(tx *sql.Tx) error {
+				_, err := tx.Exec("insert into log")
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			err = Transact(ctx, tx, func(tx *sql.Tx) error {
+				return nestedErr
+			})
+			assert.Equal(t, nestedErr, err)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = Transact(ctx, db, func(tx *sql.Tx) error {
+				return Transact(ctx, tx, func(tx *sql.Tx) error {
+					panic("test")
+				})
+			})
+		})
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid conn", func(t *testing.T) {
+		err := Transact(ctx, struct{ Conn }{db}, func(tx *sql.Tx) error {
+			return nil
+		})
+		assert.Equal(t, errNotTransactable, err)
+	})
+}